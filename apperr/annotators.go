@@ -1,6 +1,8 @@
 package apperr
 
 import (
+	"time"
+
 	"github.com/c2pc/go-pkg/apperr/utils/code"
 	"github.com/c2pc/go-pkg/apperr/utils/translate"
 )
@@ -66,3 +68,48 @@ func WithTitle(title string) Annotator {
 		err.Title = title
 	}
 }
+
+func WithField(key string, value interface{}) Annotator {
+	return func(err *Error) {
+		if key == "" {
+			return
+		}
+		err.Fields = err.Fields.merge(Fields{key: value})
+	}
+}
+
+func WithFields(fields Fields) Annotator {
+	return func(err *Error) {
+		if len(fields) == 0 {
+			return
+		}
+		err.Fields = err.Fields.merge(fields)
+	}
+}
+
+func WithRetryAfter(d time.Duration) Annotator {
+	return func(err *Error) {
+		if d <= 0 {
+			return
+		}
+		err.RetryAfter = d
+	}
+}
+
+func WithViolations(violations ...Violation) Annotator {
+	return func(err *Error) {
+		if len(violations) == 0 {
+			return
+		}
+		err.Violations = violations
+	}
+}
+
+func WithErrors(errs ...Error) Annotator {
+	return func(err *Error) {
+		if len(errs) == 0 {
+			return
+		}
+		err.Errors = errs
+	}
+}
@@ -0,0 +1,403 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/c2pc/go-pkg/apperr/utils/code"
+	"github.com/c2pc/go-pkg/apperr/utils/translate"
+	"gopkg.in/yaml.v3"
+)
+
+type File struct {
+	Package string       `yaml:"package"`
+	Errors  []Definition `yaml:"errors"`
+}
+
+type Definition struct {
+	ID   string `yaml:"id"`
+	Name string `yaml:"name"`
+	// Code is a code name such as NotFound or the number of a custom code.
+	Code        string            `yaml:"code"`
+	Context     string            `yaml:"context"`
+	ShowMessage bool              `yaml:"show_message"`
+	Title       map[string]string `yaml:"title"`
+	Text        map[string]string `yaml:"text"`
+	Args        []string          `yaml:"args"`
+	TitleArgs   []string          `yaml:"title_args"`
+}
+
+func Parse(data []byte) (File, error) {
+	var file File
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return File{}, err
+	}
+	return file, nil
+}
+
+var verbRegexp = regexp.MustCompile(`%(\[(\d+)\])?[-+# 0]*(\d+|\*)?(\.(\d+|\*))?[a-zA-Z%]`)
+
+// countArgs returns the number of arguments the fmt format string consumes.
+func countArgs(format string) int {
+	count, next := 0, 0
+	for _, m := range verbRegexp.FindAllStringSubmatch(format, -1) {
+		if m[0] == "%%" {
+			continue
+		}
+		if m[2] != "" {
+			next, _ = strconv.Atoi(m[2])
+		} else {
+			next++
+		}
+		if next > count {
+			count = next
+		}
+	}
+	return count
+}
+
+// argCount returns the argument count shared by every translation.
+func argCount(id, field string, tr map[string]string) (int, error) {
+	count := -1
+	for _, lang := range sortedKeys(tr) {
+		n := countArgs(tr[lang])
+		if count != -1 && n != count {
+			return 0, fmt.Errorf("%s: %s translations take different number of arguments", id, field)
+		}
+		count = n
+	}
+	if count == -1 {
+		return 0, nil
+	}
+	return count, nil
+}
+
+// namedArgs returns the ICU-style argument names shared by every translation.
+func namedArgs(id, field string, tr map[string]string) ([]string, error) {
+	var names []string
+	for i, lang := range sortedKeys(tr) {
+		n, err := translate.ArgNames(tr[lang])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s %s: %w", id, field, lang, err)
+		}
+		if i > 0 && !sameNames(names, n) {
+			return nil, fmt.Errorf("%s: %s translations take different named arguments", id, field)
+		}
+		names = n
+	}
+	return names, nil
+}
+
+func sameNames(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := map[string]bool{}
+	for _, n := range a {
+		set[n] = true
+	}
+	for _, n := range b {
+		if !set[n] {
+			return false
+		}
+	}
+	return true
+}
+
+func argNames(id, field string, names []string, count int, prefix string) ([]string, error) {
+	if len(names) == 0 {
+		for i := 0; i < count; i++ {
+			names = append(names, prefix+strconv.Itoa(i))
+		}
+		return names, nil
+	}
+	if len(names) != count {
+		return nil, fmt.Errorf("%s: %s declares %d arguments, translations take %d", id, field, len(names), count)
+	}
+	return names, nil
+}
+
+// reservedParams are the identifiers a generated constructor refers to.
+var reservedParams = map[string]bool{"apperr": true, "code": true, "translate": true}
+
+// checkParams rejects parameter names that are not identifiers or shadow an
+// identifier used by the constructor of name.
+func checkParams(id, name string, params []string) error {
+	for _, param := range params {
+		if !token.IsIdentifier(param) {
+			return fmt.Errorf("%s: invalid argument name %q", id, param)
+		}
+		if reservedParams[param] || param == name {
+			return fmt.Errorf("%s: argument name %q shadows %s", id, param, param)
+		}
+	}
+	return nil
+}
+
+// uniqueParams returns the arguments of both lists, an argument used by both once.
+func uniqueParams(textArgs, titleArgs []string) []string {
+	var params []string
+	seen := map[string]bool{}
+	for _, arg := range append(append([]string{}, textArgs...), titleArgs...) {
+		if !seen[arg] {
+			seen[arg] = true
+			params = append(params, arg)
+		}
+	}
+	return params
+}
+
+func Generate(file File) ([]byte, error) {
+	if file.Package == "" {
+		return nil, errors.New("package name is empty")
+	}
+
+	var buf, constructors bytes.Buffer
+	imports := []string{"github.com/c2pc/go-pkg/apperr"}
+	usesCode, usesTranslate := false, false
+	ids := map[string]bool{}
+	names := map[string]string{}
+	// addName reserves a package level identifier, so that neither two
+	// variables nor two constructors end up with the same name.
+	addName := func(id, name string) error {
+		if other, ok := names[name]; ok {
+			return fmt.Errorf("%s: name %s is already used by %s", id, name, other)
+		}
+		names[name] = id
+		return nil
+	}
+
+	fmt.Fprintf(&buf, "var (\n")
+	for _, def := range file.Errors {
+		if def.ID == "" {
+			return nil, errors.New("error with empty id")
+		}
+		if ids[def.ID] {
+			return nil, fmt.Errorf("%s: duplicate id", def.ID)
+		}
+		ids[def.ID] = true
+
+		name := def.Name
+		if name == "" {
+			name = "Err" + camelCase(def.ID)
+		}
+		if !token.IsIdentifier(name) {
+			return nil, fmt.Errorf("%s: invalid name %q", def.ID, name)
+		}
+		if err := addName(def.ID, name); err != nil {
+			return nil, err
+		}
+
+		var annotators []string
+		if def.Code != "" {
+			annotator, err := codeAnnotator(def.ID, def.Code)
+			if err != nil {
+				return nil, err
+			}
+			annotators = append(annotators, annotator)
+			usesCode = true
+		}
+		if def.Context != "" {
+			annotators = append(annotators, fmt.Sprintf("apperr.WithContext(%q)", def.Context))
+		}
+		if def.ShowMessage {
+			annotators = append(annotators, "apperr.WithShowMessage(true)")
+		}
+		if len(def.Title) > 0 || len(def.Text) > 0 {
+			usesTranslate = true
+		}
+		if len(def.Title) > 0 {
+			annotators = append(annotators, fmt.Sprintf("apperr.WithTitleTranslate(%s)", translateLiteral(def.Title)))
+		}
+		if len(def.Text) > 0 {
+			annotators = append(annotators, fmt.Sprintf("apperr.WithTextTranslate(%s)", translateLiteral(def.Text)))
+		}
+
+		fmt.Fprintf(&buf, "\t%s = apperr.Register(apperr.New(%q,\n", name, def.ID)
+		for _, a := range annotators {
+			fmt.Fprintf(&buf, "\t\t%s,\n", a)
+		}
+		fmt.Fprintf(&buf, "\t))\n")
+
+		textCount, err := argCount(def.ID, "text", def.Text)
+		if err != nil {
+			return nil, err
+		}
+		titleCount, err := argCount(def.ID, "title", def.Title)
+		if err != nil {
+			return nil, err
+		}
+		textNamed, err := namedArgs(def.ID, "text", def.Text)
+		if err != nil {
+			return nil, err
+		}
+		titleNamed, err := namedArgs(def.ID, "title", def.Title)
+		if err != nil {
+			return nil, err
+		}
+		if textCount+titleCount > 0 && len(textNamed)+len(titleNamed) > 0 {
+			return nil, fmt.Errorf("%s: translations mix positional and named arguments", def.ID)
+		}
+
+		if len(textNamed) > 0 || len(titleNamed) > 0 {
+			if err := checkParams(def.ID, name, uniqueParams(textNamed, titleNamed)); err != nil {
+				return nil, err
+			}
+			if err := addName(def.ID, constructorName(name)); err != nil {
+				return nil, err
+			}
+			writeNamedConstructor(&constructors, name, textNamed, titleNamed)
+			continue
+		}
+
+		textArgs, err := argNames(def.ID, "args", def.Args, textCount, "arg")
+		if err != nil {
+			return nil, err
+		}
+		titleArgs, err := argNames(def.ID, "title_args", def.TitleArgs, titleCount, "titleArg")
+		if err != nil {
+			return nil, err
+		}
+
+		if err := checkParams(def.ID, name, uniqueParams(textArgs, titleArgs)); err != nil {
+			return nil, err
+		}
+		if len(textArgs) > 0 || len(titleArgs) > 0 {
+			if err := addName(def.ID, constructorName(name)); err != nil {
+				return nil, err
+			}
+			writeConstructor(&constructors, name, textArgs, titleArgs)
+		}
+	}
+	fmt.Fprintf(&buf, ")\n")
+	buf.Write(constructors.Bytes())
+
+	if usesCode {
+		imports = append(imports, "github.com/c2pc/go-pkg/apperr/utils/code")
+	}
+	if usesTranslate {
+		imports = append(imports, "github.com/c2pc/go-pkg/apperr/utils/translate")
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by apperrgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&src, "package %s\n\n", file.Package)
+	fmt.Fprintf(&src, "import (\n")
+	for _, i := range imports {
+		fmt.Fprintf(&src, "\t%q\n", i)
+	}
+	fmt.Fprintf(&src, ")\n\n")
+	src.Write(buf.Bytes())
+
+	return format.Source(src.Bytes())
+}
+
+// codeAnnotator returns the WithCode annotator of a code name such as NotFound
+// or of a number of a custom code registered at runtime, e.g. 1001.
+func codeAnnotator(id, name string) (string, error) {
+	if n, err := strconv.Atoi(name); err == nil {
+		if code.Code(n) < code.CustomCodeStart {
+			return "", fmt.Errorf("%s: code %d is not custom, use its name", id, n)
+		}
+		return fmt.Sprintf("apperr.WithCode(code.Code(%d))", n), nil
+	}
+
+	if _, ok := code.Parse(name); !ok {
+		return "", fmt.Errorf("%s: unknown code %q", id, name)
+	}
+	return fmt.Sprintf("apperr.WithCode(code.%s)", name), nil
+}
+
+func constructorName(name string) string {
+	return "New" + strings.TrimPrefix(name, "Err")
+}
+
+func writeConstructor(buf *bytes.Buffer, name string, textArgs, titleArgs []string) {
+	constructor := constructorName(name)
+
+	params := uniqueParams(textArgs, titleArgs)
+	fmt.Fprintf(buf, "\n// %s returns %s with its text and title arguments.\n", constructor, name)
+	fmt.Fprintf(buf, "func %s(%s any) apperr.Error {\n", constructor, strings.Join(params, ", "))
+	fmt.Fprintf(buf, "\treturn %s", name)
+	if len(textArgs) > 0 {
+		fmt.Fprintf(buf, ".WithTextArgs(%s)", strings.Join(textArgs, ", "))
+	}
+	if len(titleArgs) > 0 {
+		fmt.Fprintf(buf, ".WithTitleArgs(%s)", strings.Join(titleArgs, ", "))
+	}
+	fmt.Fprintf(buf, "\n}\n")
+}
+
+func writeNamedConstructor(buf *bytes.Buffer, name string, textArgs, titleArgs []string) {
+	constructor := constructorName(name)
+
+	params := uniqueParams(textArgs, titleArgs)
+
+	argsMap := func(args []string) string {
+		parts := make([]string, 0, len(args))
+		for _, arg := range args {
+			parts = append(parts, fmt.Sprintf("%q: %s", arg, arg))
+		}
+		return "map[string]any{" + strings.Join(parts, ", ") + "}"
+	}
+
+	fmt.Fprintf(buf, "\n// %s returns %s with its named text and title arguments.\n", constructor, name)
+	fmt.Fprintf(buf, "func %s(%s any) apperr.Error {\n", constructor, strings.Join(params, ", "))
+	fmt.Fprintf(buf, "\treturn %s", name)
+	if len(textArgs) > 0 {
+		fmt.Fprintf(buf, ".WithTextNamedArgs(%s)", argsMap(textArgs))
+	}
+	if len(titleArgs) > 0 {
+		fmt.Fprintf(buf, ".WithTitleNamedArgs(%s)", argsMap(titleArgs))
+	}
+	fmt.Fprintf(buf, "\n}\n")
+}
+
+func translateLiteral(tr map[string]string) string {
+	parts := make([]string, 0, len(tr))
+	for _, lang := range sortedKeys(tr) {
+		key := fmt.Sprintf("translate.Language(%q)", lang)
+		switch translate.Language(lang) {
+		case translate.RU:
+			key = "translate.RU"
+		case translate.EN:
+			key = "translate.EN"
+		}
+		parts = append(parts, fmt.Sprintf("%s: %q", key, tr[lang]))
+	}
+	return "translate.Translate{" + strings.Join(parts, ", ") + "}"
+}
+
+func camelCase(id string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range id {
+		if r == '_' || r == '-' || r == '.' || r == ' ' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const definitions = `
+package: errs
+errors:
+  - id: user_not_found
+    code: NotFound
+    context: users
+    show_message: true
+    text:
+      ru: "Пользователь %s не найден"
+      en: "User %s not found"
+    args: [login]
+  - id: attempts_left
+    name: ErrAttempts
+    text:
+      ru: "Осталось попыток: %[2]d из %[1]d"
+  - id: attempts_named
+    text:
+      ru: "{count, plural, one {Осталась # попытка} few {Осталось # попытки} other {Осталось # попыток}} для {login}"
+      en: "{count, plural, one {# attempt} other {# attempts}} left for {login}"
+  - id: rate_limited
+    code: "1001"
+  - id: user_blocked
+    title:
+      en: "User %s"
+    text:
+      en: "User %s is blocked"
+    args: [login]
+    title_args: [login]
+`
+
+func TestGenerate(t *testing.T) {
+	file, err := Parse([]byte(definitions))
+	assert.NoError(t, err)
+
+	src, err := Generate(file)
+	assert.NoError(t, err)
+
+	out := string(src)
+	assert.Contains(t, out, "package errs")
+	assert.Contains(t, out, `ErrUserNotFound = apperr.Register(apperr.New("user_not_found",`)
+	assert.Contains(t, out, "apperr.WithCode(code.NotFound)")
+	assert.Contains(t, out, `translate.Translate{translate.EN: "User %s not found", translate.RU: "Пользователь %s не найден"}`)
+	assert.Contains(t, out, "func NewUserNotFound(login any) apperr.Error")
+	assert.Contains(t, out, "func NewAttempts(arg0, arg1 any) apperr.Error")
+	assert.Contains(t, out, "func NewAttemptsNamed(count, login any) apperr.Error")
+	assert.Contains(t, out, "func NewUserBlocked(login any) apperr.Error")
+	assert.Contains(t, out, "apperr.WithCode(code.Code(1001))")
+}
+
+func TestGenerateCompiles(t *testing.T) {
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+
+	file, err := Parse([]byte(definitions))
+	assert.NoError(t, err)
+	src, err := Generate(file)
+	assert.NoError(t, err)
+
+	dir, err := os.MkdirTemp(".", "_generated")
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "errors.go"), src, 0o600))
+
+	out, err := exec.Command(goBin, "vet", "./"+filepath.Base(dir)).CombinedOutput()
+	assert.NoError(t, err, string(out))
+}
+
+func TestGenerateArgsMismatch(t *testing.T) {
+	_, err := Generate(File{Package: "errs", Errors: []Definition{{
+		ID:   "id",
+		Text: map[string]string{"ru": "%s %s", "en": "%s"},
+	}}})
+	assert.Error(t, err)
+
+	_, err = Generate(File{Package: "errs", Errors: []Definition{{
+		ID:   "id",
+		Text: map[string]string{"ru": "%s %d%%"},
+		Args: []string{"one"},
+	}}})
+	assert.Error(t, err)
+
+	_, err = Generate(File{Package: "errs", Errors: []Definition{{ID: "id", Code: "Missing"}}})
+	assert.Error(t, err)
+
+	for _, name := range []string{"apperr", "code", "translate", "ErrId", "type"} {
+		_, err = Generate(File{Package: "errs", Errors: []Definition{{
+			ID:   "id",
+			Text: map[string]string{"en": "%s"},
+			Args: []string{name},
+		}}})
+		assert.Error(t, err, name)
+	}
+
+	_, err = Generate(File{Package: "errs", Errors: []Definition{{
+		ID:   "id",
+		Text: map[string]string{"en": "{code}"},
+	}}})
+	assert.Error(t, err)
+
+	_, err = Generate(File{Package: "errs", Errors: []Definition{{
+		ID:    "id",
+		Text:  map[string]string{"en": "{login} is blocked"},
+		Title: map[string]string{"en": "User %s"},
+	}}})
+	assert.Error(t, err)
+
+	_, err = Generate(File{Package: "errs", Errors: []Definition{{ID: "id", Code: "5"}}})
+	assert.Error(t, err)
+}
+
+func TestGenerateNameCollision(t *testing.T) {
+	_, err := Generate(File{Package: "errs", Errors: []Definition{
+		{ID: "user_blocked"},
+		{ID: "user-blocked"},
+	}})
+	assert.Error(t, err)
+
+	_, err = Generate(File{Package: "errs", Errors: []Definition{
+		{ID: "blocked", Text: map[string]string{"en": "%s"}},
+		{ID: "blocked_custom", Name: "Blocked", Text: map[string]string{"en": "%s"}},
+	}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,42 @@
+// Command apperrgen generates apperr error declarations from a YAML file.
+//
+//	//go:generate go run github.com/c2pc/go-pkg/apperr/cmd/apperrgen -in errors.yaml -out errors_gen.go
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+)
+
+func main() {
+	in := flag.String("in", "errors.yaml", "YAML file with error definitions")
+	out := flag.String("out", "errors_gen.go", "generated Go file")
+	pkg := flag.String("package", "", "package name, overrides the one in the YAML file")
+	flag.Parse()
+
+	data, err := os.ReadFile(*in)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	file, err := Parse(data)
+	if err != nil {
+		log.Fatalf("%s: %v", *in, err)
+	}
+	if *pkg != "" {
+		file.Package = *pkg
+	}
+	if file.Package == "" {
+		file.Package = os.Getenv("GOPACKAGE")
+	}
+
+	src, err := Generate(file)
+	if err != nil {
+		log.Fatalf("%s: %v", *in, err)
+	}
+
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,69 @@
+package apperr
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/c2pc/go-pkg/level"
+)
+
+var exposureLevel atomic.Value
+
+func init() {
+	exposureLevel.Store(level.PRODUCTION)
+}
+
+// SetLevel sets the environment responses are rendered for. Unknown levels are
+// treated as level.PRODUCTION, which is also the default.
+func SetLevel(l level.Level) {
+	exposureLevel.Store(l)
+}
+
+func GetLevel() level.Level {
+	return exposureLevel.Load().(level.Level)
+}
+
+// DebugEnabled reports whether responses carry debug details, i.e. the level is
+// level.DEVELOPMENT or level.TEST.
+func DebugEnabled() bool {
+	l := GetLevel()
+	return level.Is(l, level.DEVELOPMENT) || level.Is(l, level.TEST)
+}
+
+// Debug is the debug section of a response, it is never rendered in production.
+type Debug struct {
+	// IDs are the IDs of the chain from the outermost error.
+	IDs []string `json:"ids"`
+	// Error is the text of LastError.
+	Error string `json:"error,omitempty"`
+	// Stack is the innermost call-site stack recorded in the chain.
+	Stack []string `json:"stack,omitempty"`
+}
+
+// Debug returns the debug section of err, nil unless DebugEnabled.
+func (e Error) Debug() *Debug {
+	if !DebugEnabled() {
+		return nil
+	}
+
+	d := &Debug{}
+	var stack StackTrace
+	for cur, ok := e, true; ok; {
+		d.IDs = append(d.IDs, cur.ID)
+		if len(cur.stack) > 0 {
+			stack = cur.stack
+		}
+		ok = errors.As(cur.Err, &cur)
+	}
+
+	if lastError := e.LastError(); lastError != nil {
+		d.Error = lastError.Error()
+	}
+
+	for _, f := range stack {
+		d.Stack = append(d.Stack, fmt.Sprintf("%s %s:%d", f.Func(), f.File(), f.Line()))
+	}
+
+	return d
+}
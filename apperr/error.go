@@ -3,28 +3,43 @@ package apperr
 import (
 	"errors"
 	"fmt"
+	"io"
 	"strings"
+	"time"
 
 	"github.com/c2pc/go-pkg/apperr/utils/code"
 	"github.com/c2pc/go-pkg/apperr/utils/translate"
 )
 
 type Error struct {
-	ID                 string
-	Context            string
-	ShowMessage        bool
-	Code               code.Code
-	Text               string
-	TextTranslate      translate.Translate
-	TextTranslateArgs  []interface{}
-	Title              string
-	TitleTranslate     translate.Translate
-	TitleTranslateArgs []interface{}
-	Err                error
+	ID                      string
+	Context                 string
+	ShowMessage             bool
+	Code                    code.Code
+	Text                    string
+	TextTranslate           translate.Translate
+	TextTranslateArgs       []interface{}
+	TextTranslateNamedArgs  map[string]interface{}
+	Title                   string
+	TitleTranslate          translate.Translate
+	TitleTranslateArgs      []interface{}
+	TitleTranslateNamedArgs map[string]interface{}
+	Fields                  Fields
+	Errors                  []Error
+	Violations              []Violation
+	RetryAfter              time.Duration
+	Err                     error
+	stack                   StackTrace
 }
 
+// New records the call-site stack, except during package initialization:
+// the stack of a package level sentinel would only show init frames, the one
+// of WithError shows where it is returned.
 func New(id string, annotators ...Annotator) Error {
-	err := Error{}
+	err := Error{stack: callers(1)}
+	if err.stack.initializing() {
+		err.stack = nil
+	}
 
 	WithID(id)(&err)
 
@@ -44,16 +59,82 @@ func Replace(err Error, annotators ...Annotator) Error {
 }
 
 func (e Error) Error() string {
+	if len(e.Errors) > 0 {
+		return e.ID + joinedString(e.Errors)
+	}
+
 	if e.Err == nil {
 		return e.ID
 	}
 
+	if causes, ok := multiError(e.Err); ok {
+		parts := make([]string, 0, len(causes))
+		for _, cause := range causes {
+			parts = append(parts, causeString(cause))
+		}
+		return e.ID + "[" + strings.Join(parts, "; ") + "]"
+	}
+
+	return e.ID + "." + causeString(e.Err)
+}
+
+func causeString(err error) string {
 	var appError Error
-	if errors.As(e.Err, &appError) {
-		return e.ID + "." + e.Err.Error()
+	if errors.As(err, &appError) {
+		return err.Error()
+	}
+	return fmt.Sprintf("(%s)", err.Error())
+}
+
+func formatCause(s fmt.State, verb rune, err error) {
+	var appError Error
+	if errors.As(err, &appError) {
+		_, _ = io.WriteString(s, "\n")
+		appError.Format(s, verb)
+		return
+	}
+
+	fmt.Fprintf(s, "\n(%+v)", err)
+}
+
+// multiError returns the causes of an errors.Join style error other than Error.
+func multiError(err error) ([]error, bool) {
+	if _, ok := err.(Error); ok {
+		return nil, false
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap(), true
+	}
+	return nil, false
+}
+
+// Unwrap returns the children of Join followed by the cause, so that errors.Is
+// and errors.As see through an Error.
+func (e Error) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors)+1)
+	for _, child := range e.Errors {
+		errs = append(errs, child)
+	}
+	if e.Err != nil {
+		errs = append(errs, e.Err)
 	}
+	return errs
+}
+
+// Is reports whether target is an Error with the same ID. errors.Is calls it
+// for every Error of the chain.
+func (e Error) Is(target error) bool {
+	t, ok := target.(Error)
+	return ok && t.ID == e.ID
+}
 
-	return e.ID + "." + fmt.Sprintf("(%s)", e.Err.Error())
+// As returns the first Error of the chain of err.
+func As(err error) (Error, bool) {
+	var appError Error
+	if errors.As(err, &appError) {
+		return appError, true
+	}
+	return Error{}, false
 }
 
 func (e Error) LastError() error {
@@ -67,9 +148,52 @@ func (e Error) LastError() error {
 
 func (e Error) WithError(err error) Error {
 	e.Err = err
+	e.stack = callers(1)
 	return e
 }
 
+// StackTrace returns the call-site stack recorded by New or WithError.
+func (e Error) StackTrace() StackTrace {
+	return e.stack
+}
+
+// Format implements fmt.Formatter. %s and %v print Error(),
+// %+v prints every ID of the chain followed by its stack trace.
+func (e Error) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			_, _ = io.WriteString(s, e.ID)
+			if len(e.Fields) > 0 {
+				_, _ = io.WriteString(s, " "+e.Fields.String())
+			}
+			e.stack.Format(s, verb)
+			for _, child := range e.Errors {
+				_, _ = io.WriteString(s, "\n")
+				child.Format(s, verb)
+			}
+			if e.Err == nil {
+				return
+			}
+
+			if causes, ok := multiError(e.Err); ok {
+				for _, cause := range causes {
+					formatCause(s, verb, cause)
+				}
+				return
+			}
+
+			formatCause(s, verb, e.Err)
+			return
+		}
+		fallthrough
+	case 's':
+		_, _ = io.WriteString(s, e.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	}
+}
+
 func (e Error) WithTextArgs(args ...any) Error {
 	e.TextTranslateArgs = args
 	return e
@@ -80,18 +204,46 @@ func (e Error) WithTitleArgs(args ...any) Error {
 	return e
 }
 
+func (e Error) WithField(key string, value any) Error {
+	WithField(key, value)(&e)
+	return e
+}
+
+func (e Error) WithFields(fields Fields) Error {
+	WithFields(fields)(&e)
+	return e
+}
+
+func (e Error) WithViolations(violations ...Violation) Error {
+	WithViolations(violations...)(&e)
+	return e
+}
+
+func (e Error) WithRetryAfter(d time.Duration) Error {
+	WithRetryAfter(d)(&e)
+	return e
+}
+
+// WithTextNamedArgs sets the arguments of an ICU-style text, see translate.FormatNamed.
+// They are used instead of the positional ones when set.
+func (e Error) WithTextNamedArgs(args map[string]any) Error {
+	e.TextTranslateNamedArgs = args
+	return e
+}
+
+// WithTitleNamedArgs sets the arguments of an ICU-style title, see translate.FormatNamed.
+func (e Error) WithTitleNamedArgs(args map[string]any) Error {
+	e.TitleTranslateNamedArgs = args
+	return e
+}
+
 func (e Error) NewID(id string) Error {
 	e.ID = id
 	return e
 }
 
+// Is reports whether err matches target. Errors match by ID anywhere in the chain.
 func Is(err, target error) bool {
-	var appError Error
-	var appTarget Error
-	if errors.As(err, &appError) && errors.As(target, &appTarget) {
-		return appError.ID == appTarget.ID
-	}
-
 	return errors.Is(err, target)
 }
 
@@ -103,10 +255,56 @@ func (e Error) GetIDSuffix() string {
 	return ids[len(ids)-1]
 }
 
+func (e Error) GetIDPrefix() string {
+	return strings.Split(e.ID, ".")[0]
+}
+
+func formatMessage(lang translate.Language, message string, args []interface{}, namedArgs map[string]interface{}) string {
+	if namedArgs != nil {
+		return translate.FormatNamed(lang, message, namedArgs)
+	}
+	return translate.Format(message, args...)
+}
+
+func lookupMessage(lang string, ids ...string) (translate.Message, bool) {
+	for _, id := range ids {
+		if msg, ok := translate.Lookup(lang, id); ok {
+			return msg, true
+		}
+	}
+	return translate.Message{}, false
+}
+
+// Translate returns the title and text of err in lang. Errors without inline
+// translations are looked up in translate.DefaultBundle: the title by the first
+// and the text by the last ID of the chain.
 func Translate(err Error, lang string) (string, string) {
-	title := err.TitleTranslate.Translate(lang, err.TitleTranslateArgs...)
-	text := err.TextTranslate.Translate(lang, err.TextTranslateArgs...)
+	var title, text string
+	if err.TitleTranslateNamedArgs != nil {
+		title = err.TitleTranslate.TranslateNamed(lang, err.TitleTranslateNamedArgs)
+	} else {
+		title = err.TitleTranslate.Translate(lang, err.TitleTranslateArgs...)
+	}
+	if err.TextTranslateNamedArgs != nil {
+		text = err.TextTranslate.TranslateNamed(lang, err.TextTranslateNamedArgs)
+	} else {
+		text = err.TextTranslate.Translate(lang, err.TextTranslateArgs...)
+	}
 
+	if len(err.TitleTranslate) == 0 {
+		if msg, ok := lookupMessage(lang, err.ID, err.GetIDPrefix()); ok && msg.Title != "" {
+			title = formatMessage(msg.Language, msg.Title, err.TitleTranslateArgs, err.TitleTranslateNamedArgs)
+		}
+	}
+	if len(err.TextTranslate) == 0 {
+		if msg, ok := lookupMessage(lang, err.ID, err.GetIDSuffix()); ok && msg.Text != "" {
+			text = formatMessage(msg.Language, msg.Text, err.TextTranslateArgs, err.TextTranslateNamedArgs)
+		}
+	}
+
+	if title == "" {
+		title = err.Title
+	}
 	if text == "" {
 		text = err.Text
 	}
@@ -118,19 +316,26 @@ func Unwrap(err Error) Error {
 	unwrappedError := err
 	var lastError Error
 
-	if err.Err != nil {
-		if r, ok := err.Err.(Error); ok {
-			lastError = r
-		} else {
-			return unwrappedError
-		}
+	if !errors.As(err.Err, &lastError) {
+		return unwrappedError
 	}
 
 	unwrappedError.Text = lastError.Text
 	unwrappedError.TextTranslate = lastError.TextTranslate
 	unwrappedError.TextTranslateArgs = lastError.TextTranslateArgs
+	unwrappedError.TextTranslateNamedArgs = lastError.TextTranslateNamedArgs
 	unwrappedError.Code = lastError.Code
 	unwrappedError.ShowMessage = lastError.ShowMessage
+	unwrappedError.Fields = lastError.Fields.merge(err.Fields)
+	if len(lastError.Errors) > 0 {
+		unwrappedError.Errors = lastError.Errors
+	}
+	if len(lastError.Violations) > 0 {
+		unwrappedError.Violations = lastError.Violations
+	}
+	if lastError.RetryAfter > 0 {
+		unwrappedError.RetryAfter = lastError.RetryAfter
+	}
 
 	if lastError.ID == "" {
 		unwrappedError.ID = err.ID
@@ -1,11 +1,15 @@
 package apperr
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/c2pc/go-pkg/apperr/utils/code"
 	"github.com/c2pc/go-pkg/apperr/utils/translate"
+	"github.com/c2pc/go-pkg/level"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -17,13 +21,13 @@ func TestNew(t *testing.T) {
 
 func TestWithError(t *testing.T) {
 	err := New("id").WithError(errors.New("new err"))
-	assert.Equal(t, "id:new_err", err.Error())
+	assert.Equal(t, "id.(new err)", err.Error())
 
 	err2 := New("id2").WithError(err)
-	assert.Equal(t, "id2:id:new_err", err2.Error())
+	assert.Equal(t, "id2.id.(new err)", err2.Error())
 
 	err3 := New("id3").WithError(err2)
-	assert.Equal(t, "id3:id2:id:new_err", err3.Error())
+	assert.Equal(t, "id3.id2.id.(new err)", err3.Error())
 }
 
 func TestWithID(t *testing.T) {
@@ -115,4 +119,251 @@ func TestUnwrap(t *testing.T) {
 
 	assert.Equal(t, Unwrap(err11).Error(), Unwrap(err11).Error())
 	assert.Equal(t, Unwrap(err21).Error(), Unwrap(err21).Error())
+
+	err3 := New("id3", WithCode(code.NotFound), WithText("Not found"))
+	assert.Equal(t, err3.Code, Unwrap(err3).Code)
+	assert.Equal(t, err3.Text, Unwrap(err3).Text)
+	assert.Equal(t, "id3", Unwrap(err3).ID)
+
+	err4 := New("id4").WithError(fmt.Errorf("wrapped: %w", err3))
+	assert.Equal(t, "id4.id3", Unwrap(err4).ID)
+	assert.Equal(t, code.NotFound, Unwrap(err4).Code)
+}
+
+func TestStdlibInterop(t *testing.T) {
+	errNoRows := errors.New("no rows")
+	errNotFound := New("not_found", WithCode(code.NotFound))
+
+	err := New("method").WithError(errNotFound.WithError(fmt.Errorf("query: %w", errNoRows)))
+	assert.True(t, errors.Is(err, errNoRows))
+	assert.True(t, errors.Is(err, errNotFound))
+	assert.True(t, Is(err, errNotFound))
+	assert.False(t, errors.Is(err, New("other")))
+	assert.Equal(t, []error{err.Err}, err.Unwrap())
+
+	wrapped := fmt.Errorf("handler: %w", err)
+	appErr, ok := As(wrapped)
+	assert.True(t, ok)
+	assert.Equal(t, "method", appErr.ID)
+	assert.True(t, errors.Is(wrapped, errNotFound))
+
+	_, ok = As(errNoRows)
+	assert.False(t, ok)
+
+	joined := New("batch").WithError(errors.Join(errNoRows, errNotFound))
+	assert.True(t, errors.Is(joined, errNoRows))
+	assert.True(t, errors.Is(joined, errNotFound))
+	assert.Equal(t, "batch[(no rows); not_found]", joined.Error())
+
+	multiple := Join(errNotFound.WithError(errNoRows), New("exists"))
+	assert.True(t, errors.Is(multiple, errNoRows))
+	assert.True(t, errors.Is(multiple, New("exists")))
+
+	var target Error
+	assert.True(t, errors.As(fmt.Errorf("%w", multiple), &target))
+	assert.Equal(t, JoinID, target.ID)
+}
+
+func TestStackTrace(t *testing.T) {
+	err := New("id")
+	assert.NotEmpty(t, err.StackTrace())
+	assert.Contains(t, fmt.Sprintf("%n", err.StackTrace()[0]), "TestStackTrace")
+
+	err2 := New("id2").WithError(err)
+	assert.Contains(t, fmt.Sprintf("%+v", err2), "id2\n")
+	assert.Contains(t, fmt.Sprintf("%+v", err2), "\nid\n")
+	assert.Contains(t, fmt.Sprintf("%+v", err2), "error_test.go")
+	assert.Equal(t, err2.Error(), fmt.Sprintf("%v", err2))
+	assert.Equal(t, err2.Error(), fmt.Sprintf("%s", err2))
+
+	SetStackTrace(false)
+	defer SetStackTrace(true)
+
+	err3 := New("id3").WithError(errors.New("cause"))
+	assert.Empty(t, err3.StackTrace())
+	assert.Equal(t, "id3\n(cause)", fmt.Sprintf("%+v", err3))
+}
+
+var errSentinel = New("sentinel")
+
+func TestSentinelStack(t *testing.T) {
+	assert.Empty(t, errSentinel.StackTrace())
+
+	SetLevel(level.TEST)
+	defer SetLevel(level.PRODUCTION)
+
+	err := New("method").WithError(errSentinel.WithError(errors.New("cause")))
+	debug := err.Debug()
+	assert.Equal(t, []string{"method", "sentinel"}, debug.IDs)
+	assert.Contains(t, debug.Stack[0], "TestSentinelStack")
+
+	joined := Join(errSentinel, New("other"))
+	assert.Contains(t, fmt.Sprintf("%n", joined.StackTrace()[0]), "TestSentinelStack")
+}
+
+func TestWithFields(t *testing.T) {
+	base := New("id", WithField("limit", 10))
+	err := base.WithField("user_id", 42).WithFields(Fields{"limit": 20})
+
+	assert.Equal(t, Fields{"limit": 10}, base.Fields)
+	assert.Equal(t, Fields{"limit": 20, "user_id": 42}, err.Fields)
+
+	wrapped := New("id2", WithField("op", "update")).WithError(err)
+	assert.Equal(t, Fields{"limit": 20, "user_id": 42, "op": "update"}, Unwrap(wrapped).Fields)
+	assert.Contains(t, fmt.Sprintf("%+v", wrapped), "id [limit=20 user_id=42]")
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	err := r.Register(New("not_found", WithCode(code.NotFound), WithTextTranslate(translate.Translate{translate.RU: "Не найдено"})))
+
+	assert.Equal(t, "not_found", err.ID)
+	assert.NoError(t, r.Validate())
+
+	catalog := r.Catalog()
+	assert.Len(t, catalog, 1)
+	assert.Equal(t, 404, catalog[0].HttpStatus)
+	assert.Equal(t, "NotFound", catalog[0].Code)
+
+	r.Register(New("not_found", WithCode(code.NotFound), WithTextTranslate(translate.Translate{translate.RU: "Не найдено"})))
+	assert.Error(t, r.Validate())
+	assert.Len(t, r.Collisions()["not_found"], 2)
+
+	r.Register(New("not_found", WithCode(code.Internal)))
+	assert.Len(t, r.Collisions()["not_found"], 3)
+
+	data, e := r.OpenAPI()
+	assert.NoError(t, e)
+	assert.Contains(t, string(data), `"not_found"`)
+}
+
+func TestJoin(t *testing.T) {
+	errNotFound := New("not_found", WithCode(code.NotFound))
+	errExists := New("exists", WithCode(code.AlreadyExists))
+	errInternal := New("internal", WithCode(code.Internal))
+
+	err := Join(errNotFound, errNotFound.WithField("row", 2))
+	assert.Equal(t, JoinID, err.ID)
+	assert.Equal(t, code.NotFound, err.Code)
+	assert.Len(t, err.Errors, 2)
+	assert.Equal(t, "multiple_errors[not_found; not_found]", err.Error())
+
+	assert.Equal(t, code.InvalidArgument, Join(errNotFound, errExists).Code)
+	assert.Equal(t, code.Internal, Join(errNotFound, errInternal).Code)
+	assert.Equal(t, code.NotFound, Join(New("wrapper").WithError(errNotFound)).Code)
+
+	wrapped := New("import").WithError(Join(errNotFound, errExists))
+	assert.Len(t, Unwrap(wrapped).Errors, 2)
+	assert.Equal(t, code.InvalidArgument, Unwrap(wrapped).Code)
+}
+
+func TestRetryable(t *testing.T) {
+	err := New("busy", WithCode(code.Unavailable))
+	assert.True(t, err.Retryable())
+	assert.Equal(t, time.Duration(0), err.GetRetryAfter())
+
+	wrapped := New("method").WithError(New("limit", WithCode(code.InvalidArgument)).WithRetryAfter(5 * time.Second))
+	assert.True(t, IsRetryable(wrapped))
+	assert.Equal(t, 5*time.Second, wrapped.GetRetryAfter())
+	assert.Equal(t, 5*time.Second, Unwrap(wrapped).RetryAfter)
+
+	assert.False(t, New("bad", WithCode(code.InvalidArgument)).Retryable())
+	assert.False(t, IsRetryable(errors.New("plain")))
+}
+
+func TestTranslateBundle(t *testing.T) {
+	bundle := translate.NewBundle()
+	assert.NoError(t, bundle.LoadDir("utils/translate/testdata"))
+
+	defaultBundle := translate.DefaultBundle
+	translate.DefaultBundle = bundle
+	translate.SetFallback("kk", translate.RU, translate.EN)
+	t.Cleanup(func() {
+		translate.DefaultBundle = defaultBundle
+		translate.SetFallback("kk")
+	})
+
+	err := New("auth", WithTitleTranslate(translate.Translate{translate.RU: "Попытка авторизации"})).
+		WithError(New("not_found_error").WithTextArgs("user"))
+	appErr := Unwrap(err)
+
+	title, text := Translate(appErr, "kk")
+	assert.Equal(t, "Попытка авторизации", title)
+	assert.Equal(t, "user табылмады", text)
+
+	title, text = Translate(Unwrap(New("auth").WithError(New("not_found_error").WithTextArgs("user"))), "en")
+	assert.Equal(t, "", title)
+	assert.Equal(t, "user not found", text)
+
+	title, _ = Translate(New("auth"), "kk")
+	assert.Equal(t, "Авторизация әрекеті", title)
+
+	assert.Equal(t, "Попытка авторизации", translate.Translate{translate.RU: "Попытка авторизации"}.Translate("kk"))
+	assert.Contains(t, bundle.Languages(), translate.Language("kk"))
+	assert.NotContains(t, translate.Languages(), translate.Language("kk"))
+}
+
+func TestTranslateNamedArgs(t *testing.T) {
+	err := New("attempts", WithTextTranslate(translate.Translate{
+		translate.RU: "Осталось {count, plural, one {# попытка} few {# попытки} other {# попыток}} для {login}",
+		translate.EN: "{login} has {count, plural, one {# attempt} other {# attempts}} left",
+	})).WithTextNamedArgs(map[string]any{"count": 3, "login": "admin"})
+
+	_, text := Translate(Unwrap(New("method").WithError(err)), "ru")
+	assert.Equal(t, "Осталось 3 попытки для admin", text)
+
+	_, text = Translate(err, "en")
+	assert.Equal(t, "admin has 3 attempts left", text)
+}
+
+func TestMarshal(t *testing.T) {
+	err := New("method", WithContext("users"), WithTitle("Method")).WithError(
+		New("conflict",
+			WithCode(code.AlreadyExists),
+			WithShowMessage(true),
+			WithTextTranslate(translate.Translate{translate.RU: "Логин %s занят"}),
+		).
+			WithTextArgs("admin").
+			WithField("user_id", 42).
+			WithViolations(Violation{Column: "login", Error: "is taken", Tag: "unique"}).
+			WithRetryAfter(time.Second).
+			WithError(fmt.Errorf("pq: %w", errors.New("duplicate key"))),
+	)
+
+	data, jsonErr := json.Marshal(err)
+	assert.NoError(t, jsonErr)
+
+	var fromJSON Error
+	assert.NoError(t, json.Unmarshal(data, &fromJSON))
+
+	data, binErr := err.MarshalBinary()
+	assert.NoError(t, binErr)
+
+	var fromBinary Error
+	assert.NoError(t, fromBinary.UnmarshalBinary(data))
+
+	for _, got := range []Error{fromJSON, fromBinary} {
+		assert.Equal(t, err.Error(), got.Error())
+		assert.EqualError(t, got.LastError(), "pq: duplicate key")
+
+		title, text := Translate(Unwrap(got), "ru")
+		assert.Equal(t, "Method", title)
+		assert.Equal(t, "Логин admin занят", text)
+
+		appErr := Unwrap(got)
+		assert.Equal(t, code.AlreadyExists, appErr.Code)
+		assert.Equal(t, "users", appErr.Context)
+		assert.True(t, appErr.ShowMessage)
+		assert.Equal(t, []Violation{{Column: "login", Error: "is taken", Tag: "unique"}}, appErr.Violations)
+		assert.Equal(t, time.Second, appErr.RetryAfter)
+	}
+	assert.Equal(t, Fields{"user_id": float64(42)}, Unwrap(fromJSON).Fields)
+	assert.Equal(t, Fields{"user_id": 42}, Unwrap(fromBinary).Fields)
+
+	joined := Join(New("a", WithCode(code.NotFound)), New("b", WithCode(code.NotFound)))
+	data, _ = json.Marshal(joined)
+	var fromJoined Error
+	assert.NoError(t, json.Unmarshal(data, &fromJoined))
+	assert.Equal(t, joined.Error(), fromJoined.Error())
+	assert.Equal(t, code.NotFound, fromJoined.GetCode())
 }
@@ -0,0 +1,41 @@
+package apperr
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Fields is a set of typed key/value metadata attached to an Error.
+type Fields map[string]interface{}
+
+// merge returns a new Fields with the values of other written over f.
+func (f Fields) merge(other Fields) Fields {
+	if len(f) == 0 && len(other) == 0 {
+		return nil
+	}
+
+	fields := make(Fields, len(f)+len(other))
+	for k, v := range f {
+		fields[k] = v
+	}
+	for k, v := range other {
+		fields[k] = v
+	}
+	return fields
+}
+
+// String returns the fields as "[k1=v1 k2=v2]" sorted by key.
+func (f Fields) String() string {
+	keys := make([]string, 0, len(f))
+	for k := range f {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%v", k, f[k]))
+	}
+	return "[" + strings.Join(parts, " ") + "]"
+}
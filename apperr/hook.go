@@ -0,0 +1,51 @@
+package apperr
+
+import (
+	"context"
+	"sync"
+)
+
+const (
+	TransportHTTP = "http"
+	TransportGRPC = "grpc"
+)
+
+// Transport describes the request an error response was rendered for.
+type Transport struct {
+	// Name is TransportHTTP or TransportGRPC.
+	Name string
+	// Method is the HTTP method or the gRPC full method name, e.g. /users.Users/Get.
+	Method string
+	// Path is the HTTP request path, empty for gRPC.
+	Path string
+}
+
+// ResponseHook is called with the full error chain of every rendered error response.
+type ResponseHook func(ctx context.Context, err Error, transport Transport)
+
+var (
+	hooksMu sync.RWMutex
+	hooks   []ResponseHook
+)
+
+// OnResponse registers a hook called by httperr.Response and grpcerr.Response.
+// Hooks run synchronously in registration order, a slow hook delays the response.
+func OnResponse(hook ResponseHook) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	hooks = append(hooks, hook)
+}
+
+// NotifyResponse calls the registered hooks, a panicking hook does not affect the others.
+func NotifyResponse(ctx context.Context, err Error, transport Transport) {
+	hooksMu.RLock()
+	hs := hooks
+	hooksMu.RUnlock()
+
+	for _, hook := range hs {
+		func() {
+			defer func() { _ = recover() }()
+			hook(ctx, err, transport)
+		}()
+	}
+}
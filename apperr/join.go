@@ -0,0 +1,86 @@
+package apperr
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/c2pc/go-pkg/apperr/utils/code"
+)
+
+const JoinID = "multiple_errors"
+
+// Join returns an aggregate error holding every non-empty child.
+// Its code is chosen by JoinCode.
+func Join(errs ...Error) Error {
+	var children []Error
+	for _, err := range errs {
+		if err.ID == "" && err.Err == nil {
+			continue
+		}
+		children = append(children, err)
+	}
+
+	codes := make([]code.Code, 0, len(children))
+	for _, child := range children {
+		codes = append(codes, child.GetCode())
+	}
+
+	err := Error{stack: callers(1), Errors: children}
+	WithID(JoinID)(&err)
+	WithCode(JoinCode(codes...))(&err)
+	return err
+}
+
+// GetCode returns the first non-OK code of the chain.
+func (e Error) GetCode() code.Code {
+	if e.Code != code.OK {
+		return e.Code
+	}
+
+	var appError Error
+	if errors.As(e.Err, &appError) {
+		return appError.GetCode()
+	}
+
+	return code.OK
+}
+
+// JoinCode chooses the code of an aggregate:
+//   - all children share a code: that code;
+//   - any child is a server side failure: the first of Internal, DataLoss,
+//     Unknown, Unavailable, DeadlineExceeded, Unimplemented found;
+//   - otherwise: InvalidArgument.
+func JoinCode(codes ...code.Code) code.Code {
+	if len(codes) == 0 {
+		return code.Unknown
+	}
+
+	same := true
+	for _, c := range codes[1:] {
+		if c != codes[0] {
+			same = false
+			break
+		}
+	}
+	if same {
+		return codes[0]
+	}
+
+	for _, server := range []code.Code{code.Internal, code.DataLoss, code.Unknown, code.Unavailable, code.DeadlineExceeded, code.Unimplemented} {
+		for _, c := range codes {
+			if c == server {
+				return server
+			}
+		}
+	}
+
+	return code.InvalidArgument
+}
+
+func joinedString(errs []Error) string {
+	parts := make([]string, 0, len(errs))
+	for _, err := range errs {
+		parts = append(parts, err.Error())
+	}
+	return "[" + strings.Join(parts, "; ") + "]"
+}
@@ -0,0 +1,173 @@
+package apperr
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/c2pc/go-pkg/apperr/utils/code"
+	"github.com/c2pc/go-pkg/apperr/utils/translate"
+)
+
+// wireError is the serialized form of an Error. An apperr cause is kept as
+// Cause, any other cause degrades to its message in CauseText. Stacks are
+// not serialized.
+type wireError struct {
+	ID                      string                 `json:"id"`
+	Context                 string                 `json:"context,omitempty"`
+	ShowMessage             bool                   `json:"show_message,omitempty"`
+	Code                    code.Code              `json:"code,omitempty"`
+	Text                    string                 `json:"text,omitempty"`
+	TextTranslate           translate.Translate    `json:"text_translate,omitempty"`
+	TextTranslateArgs       []interface{}          `json:"text_translate_args,omitempty"`
+	TextTranslateNamedArgs  map[string]interface{} `json:"text_translate_named_args,omitempty"`
+	Title                   string                 `json:"title,omitempty"`
+	TitleTranslate          translate.Translate    `json:"title_translate,omitempty"`
+	TitleTranslateArgs      []interface{}          `json:"title_translate_args,omitempty"`
+	TitleTranslateNamedArgs map[string]interface{} `json:"title_translate_named_args,omitempty"`
+	Fields                  Fields                 `json:"fields,omitempty"`
+	Errors                  []Error                `json:"errors,omitempty"`
+	Violations              []Violation            `json:"violations,omitempty"`
+	RetryAfter              time.Duration          `json:"retry_after,omitempty"`
+	Cause                   *Error                 `json:"cause,omitempty"`
+	CauseText               string                 `json:"cause_text,omitempty"`
+}
+
+func (e Error) wire() wireError {
+	w := wireError{
+		ID:                      e.ID,
+		Context:                 e.Context,
+		ShowMessage:             e.ShowMessage,
+		Code:                    e.Code,
+		Text:                    e.Text,
+		TextTranslate:           e.TextTranslate,
+		TextTranslateArgs:       e.TextTranslateArgs,
+		TextTranslateNamedArgs:  e.TextTranslateNamedArgs,
+		Title:                   e.Title,
+		TitleTranslate:          e.TitleTranslate,
+		TitleTranslateArgs:      e.TitleTranslateArgs,
+		TitleTranslateNamedArgs: e.TitleTranslateNamedArgs,
+		Fields:                  e.Fields,
+		Errors:                  e.Errors,
+		Violations:              e.Violations,
+		RetryAfter:              e.RetryAfter,
+	}
+
+	var appError Error
+	if _, joined := multiError(e.Err); !joined && errors.As(e.Err, &appError) {
+		w.Cause = &appError
+	} else if e.Err != nil {
+		w.CauseText = e.Err.Error()
+	}
+
+	return w
+}
+
+func (w wireError) error() Error {
+	e := Error{
+		ID:                      w.ID,
+		Context:                 w.Context,
+		ShowMessage:             w.ShowMessage,
+		Code:                    w.Code,
+		Text:                    w.Text,
+		TextTranslate:           w.TextTranslate,
+		TextTranslateArgs:       w.TextTranslateArgs,
+		TextTranslateNamedArgs:  w.TextTranslateNamedArgs,
+		Title:                   w.Title,
+		TitleTranslate:          w.TitleTranslate,
+		TitleTranslateArgs:      w.TitleTranslateArgs,
+		TitleTranslateNamedArgs: w.TitleTranslateNamedArgs,
+		Fields:                  w.Fields,
+		Errors:                  w.Errors,
+		Violations:              w.Violations,
+		RetryAfter:              w.RetryAfter,
+	}
+
+	switch {
+	case w.Cause != nil:
+		e.Err = *w.Cause
+	case w.CauseText != "":
+		e.Err = errors.New(w.CauseText)
+	}
+
+	return e
+}
+
+// MarshalJSON encodes the whole chain. Numeric arguments and fields come
+// back as float64, as usual with encoding/json.
+func (e Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.wire())
+}
+
+func (e *Error) UnmarshalJSON(data []byte) error {
+	var w wireError
+	if err := json.Unmarshal(data, &w); err != nil {
+		return err
+	}
+	*e = w.error()
+	return nil
+}
+
+// MarshalBinary encodes the whole chain with encoding/gob. Arguments and
+// fields keep their type if it is a bool, number, string or []byte and are
+// formatted with fmt.Sprint otherwise.
+func (e Error) MarshalBinary() ([]byte, error) {
+	w := e.wire()
+	w.TextTranslateArgs = gobArgs(w.TextTranslateArgs)
+	w.TextTranslateNamedArgs = gobNamedArgs(w.TextTranslateNamedArgs)
+	w.TitleTranslateArgs = gobArgs(w.TitleTranslateArgs)
+	w.TitleTranslateNamedArgs = gobNamedArgs(w.TitleTranslateNamedArgs)
+	w.Fields = Fields(gobNamedArgs(w.Fields))
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(w); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (e *Error) UnmarshalBinary(data []byte) error {
+	var w wireError
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&w); err != nil {
+		return err
+	}
+	*e = w.error()
+	return nil
+}
+
+func gobValue(v interface{}) interface{} {
+	switch v.(type) {
+	case nil, bool, string, []byte,
+		int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64,
+		float32, float64:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func gobArgs(args []interface{}) []interface{} {
+	if args == nil {
+		return nil
+	}
+	out := make([]interface{}, len(args))
+	for i, v := range args {
+		out[i] = gobValue(v)
+	}
+	return out
+}
+
+func gobNamedArgs(args map[string]interface{}) map[string]interface{} {
+	if args == nil {
+		return nil
+	}
+	out := make(map[string]interface{}, len(args))
+	for k, v := range args {
+		out[k] = gobValue(v)
+	}
+	return out
+}
@@ -0,0 +1,202 @@
+package apperr
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/c2pc/go-pkg/apperr/utils/code"
+	"github.com/c2pc/go-pkg/apperr/utils/translate"
+)
+
+// DefaultRegistry is the catalog used by Register.
+var DefaultRegistry = NewRegistry()
+
+// Register adds err to DefaultRegistry and returns it unchanged,
+// so it can wrap apperr.New in package level declarations.
+//
+// Registration is explicit: New does not register, since it also builds
+// ad-hoc errors at runtime. Declared errors are wrapped with Register, as
+// apperrgen does for the errors it generates.
+func Register(err Error) Error {
+	return DefaultRegistry.Register(err)
+}
+
+// Registry is a catalog of declared errors keyed by ID.
+type Registry struct {
+	mu         sync.RWMutex
+	errors     map[string]Error
+	collisions map[string][]Error
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		errors:     map[string]Error{},
+		collisions: map[string][]Error{},
+	}
+}
+
+// Register adds err to the registry and returns it unchanged. Registering
+// an ID again is recorded as a collision, even with the same definition.
+func (r *Registry) Register(err Error) Error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	registered, ok := r.errors[err.ID]
+	if !ok {
+		r.errors[err.ID] = err
+		return err
+	}
+
+	if len(r.collisions[err.ID]) == 0 {
+		r.collisions[err.ID] = []Error{registered}
+	}
+	r.collisions[err.ID] = append(r.collisions[err.ID], err)
+
+	return err
+}
+
+// Lookup returns the registered error with the given ID.
+func (r *Registry) Lookup(id string) (Error, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	err, ok := r.errors[id]
+	return err, ok
+}
+
+// Errors returns every registered error sorted by ID.
+func (r *Registry) Errors() []Error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	errs := make([]Error, 0, len(r.errors))
+	for _, err := range r.errors {
+		errs = append(errs, err)
+	}
+	sort.Slice(errs, func(i, j int) bool { return errs[i].ID < errs[j].ID })
+	return errs
+}
+
+// Collisions returns the definitions of every ID registered more than once.
+func (r *Registry) Collisions() map[string][]Error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	collisions := make(map[string][]Error, len(r.collisions))
+	for id, errs := range r.collisions {
+		collisions[id] = append([]Error(nil), errs...)
+	}
+	return collisions
+}
+
+// Validate returns an error listing the colliding IDs, if any.
+func (r *Registry) Validate() error {
+	collisions := r.Collisions()
+	if len(collisions) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(collisions))
+	for id := range collisions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return fmt.Errorf("apperr: duplicate error ids: %s", strings.Join(ids, ", "))
+}
+
+// CatalogEntry is the exported description of a registered error.
+type CatalogEntry struct {
+	ID          string              `json:"id"`
+	Code        string              `json:"code"`
+	HttpStatus  int                 `json:"http_status"`
+	Context     string              `json:"context,omitempty"`
+	ShowMessage bool                `json:"show_message"`
+	Title       translate.Translate `json:"title,omitempty"`
+	Text        translate.Translate `json:"text,omitempty"`
+}
+
+// Catalog returns the description of every registered error sorted by ID.
+func (r *Registry) Catalog() []CatalogEntry {
+	errs := r.Errors()
+	entries := make([]CatalogEntry, 0, len(errs))
+	for _, err := range errs {
+		title, text := err.TitleTranslate, err.TextTranslate
+		if len(title) == 0 && err.Title != "" {
+			title = translate.Translate{translate.RU: err.Title}
+		}
+		if len(text) == 0 && err.Text != "" {
+			text = translate.Translate{translate.RU: err.Text}
+		}
+
+		entries = append(entries, CatalogEntry{
+			ID:          err.ID,
+			Code:        err.Code.String(),
+			HttpStatus:  code.CodeToHttp(err.Code),
+			Context:     err.Context,
+			ShowMessage: err.ShowMessage,
+			Title:       title,
+			Text:        text,
+		})
+	}
+	return entries
+}
+
+// JSON returns the catalog as a JSON array.
+func (r *Registry) JSON() ([]byte, error) {
+	return json.MarshalIndent(r.Catalog(), "", "  ")
+}
+
+// OpenAPI returns the catalog as an OpenAPI 3 fragment with one
+// components/responses entry per error ID.
+func (r *Registry) OpenAPI() ([]byte, error) {
+	responses := map[string]interface{}{}
+	for _, entry := range r.Catalog() {
+		example := map[string]interface{}{
+			"id":                  entry.ID,
+			"title":               entry.Title.Translate(string(translate.RU)),
+			"text":                entry.Text.Translate(string(translate.RU)),
+			"context":             entry.Context,
+			"show_message_banner": entry.ShowMessage,
+		}
+
+		description := entry.Text.Translate(string(translate.RU))
+		if description == "" {
+			description = entry.ID
+		}
+
+		responses[entry.ID] = map[string]interface{}{
+			"description":   description,
+			"x-code":        entry.Code,
+			"x-http-status": entry.HttpStatus,
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{
+					"schema":  map[string]interface{}{"$ref": "#/components/schemas/Error"},
+					"example": example,
+				},
+			},
+		}
+	}
+
+	return json.MarshalIndent(map[string]interface{}{
+		"components": map[string]interface{}{
+			"responses": responses,
+			"schemas": map[string]interface{}{
+				"Error": map[string]interface{}{
+					"type":     "object",
+					"required": []string{"id", "title", "text", "context", "show_message_banner"},
+					"properties": map[string]interface{}{
+						"id":                  map[string]string{"type": "string"},
+						"title":               map[string]string{"type": "string"},
+						"text":                map[string]string{"type": "string"},
+						"context":             map[string]string{"type": "string"},
+						"show_message_banner": map[string]string{"type": "boolean"},
+						"meta":                map[string]string{"type": "object"},
+					},
+				},
+			},
+		},
+	}, "", "  ")
+}
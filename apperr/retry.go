@@ -0,0 +1,54 @@
+package apperr
+
+import (
+	"errors"
+	"time"
+
+	"github.com/c2pc/go-pkg/apperr/utils/code"
+)
+
+// RetryableCodes are the codes of transient failures a client may retry.
+var RetryableCodes = []code.Code{
+	code.Unavailable,
+	code.ResourceExhausted,
+	code.Aborted,
+	code.DeadlineExceeded,
+}
+
+// GetRetryAfter returns the first retry hint of the chain.
+func (e Error) GetRetryAfter() time.Duration {
+	if e.RetryAfter > 0 {
+		return e.RetryAfter
+	}
+
+	var appError Error
+	if errors.As(e.Err, &appError) {
+		return appError.GetRetryAfter()
+	}
+
+	return 0
+}
+
+// Retryable reports whether the error carries a retry hint or has one of RetryableCodes.
+func (e Error) Retryable() bool {
+	if e.GetRetryAfter() > 0 {
+		return true
+	}
+
+	c := e.GetCode()
+	for _, r := range RetryableCodes {
+		if c == r {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRetryable reports whether err is an Error that is Retryable.
+func IsRetryable(err error) bool {
+	var appError Error
+	if !errors.As(err, &appError) {
+		return false
+	}
+	return appError.Retryable()
+}
@@ -0,0 +1,143 @@
+package apperr
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+const maxStackDepth = 32
+
+var stackTraceEnabled atomic.Bool
+
+func init() {
+	stackTraceEnabled.Store(true)
+}
+
+// SetStackTrace enables or disables capturing of call-site stacks in New and WithError.
+func SetStackTrace(enabled bool) {
+	stackTraceEnabled.Store(enabled)
+}
+
+// StackTraceEnabled reports whether call-site stacks are captured.
+func StackTraceEnabled() bool {
+	return stackTraceEnabled.Load()
+}
+
+// Frame is a single program counter of a stack trace.
+type Frame uintptr
+
+func (f Frame) pc() uintptr { return uintptr(f) - 1 }
+
+// Func returns the name of the function of the frame.
+func (f Frame) Func() string {
+	fn := runtime.FuncForPC(f.pc())
+	if fn == nil {
+		return "unknown"
+	}
+	return fn.Name()
+}
+
+// File returns the full path to the file of the frame.
+func (f Frame) File() string {
+	fn := runtime.FuncForPC(f.pc())
+	if fn == nil {
+		return "unknown"
+	}
+	file, _ := fn.FileLine(f.pc())
+	return file
+}
+
+// Line returns the line number of the frame.
+func (f Frame) Line() int {
+	fn := runtime.FuncForPC(f.pc())
+	if fn == nil {
+		return 0
+	}
+	_, line := fn.FileLine(f.pc())
+	return line
+}
+
+// Format formats the frame according to the fmt.Formatter interface.
+//
+//	%s    source file
+//	%d    source line
+//	%n    function name
+//	%v    equivalent to %s:%d
+//	%+v   function name and path of source file
+func (f Frame) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 's':
+		if s.Flag('+') {
+			_, _ = io.WriteString(s, f.Func())
+			_, _ = io.WriteString(s, "\n\t")
+			_, _ = io.WriteString(s, f.File())
+		} else {
+			_, _ = io.WriteString(s, path.Base(f.File()))
+		}
+	case 'd':
+		_, _ = io.WriteString(s, strconv.Itoa(f.Line()))
+	case 'n':
+		_, _ = io.WriteString(s, path.Base(f.Func()))
+	case 'v':
+		f.Format(s, 's')
+		_, _ = io.WriteString(s, ":")
+		f.Format(s, 'd')
+	}
+}
+
+// StackTrace is a stack of frames from innermost (newest) to outermost (oldest).
+type StackTrace []Frame
+
+// Format formats the stack of frames according to the fmt.Formatter interface.
+//
+//	%s	lists source files for each frame in the stack
+//	%v	lists source file and line number for each frame in the stack
+//	%+v   prints filename, function and line number for each frame in the stack
+func (st StackTrace) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			for _, f := range st {
+				_, _ = io.WriteString(s, "\n")
+				f.Format(s, verb)
+			}
+			return
+		}
+		fmt.Fprintf(s, "%v", []Frame(st))
+	case 's':
+		fmt.Fprintf(s, "%s", []Frame(st))
+	}
+}
+
+// initializing reports whether st was captured during package initialization,
+// as for package level sentinels.
+func (st StackTrace) initializing() bool {
+	if len(st) == maxStackDepth {
+		return false
+	}
+	for i := len(st) - 1; i >= 0 && i >= len(st)-4; i-- {
+		if strings.HasPrefix(st[i].Func(), "runtime.doInit") {
+			return true
+		}
+	}
+	return false
+}
+
+func callers(skip int) StackTrace {
+	if !stackTraceEnabled.Load() {
+		return nil
+	}
+
+	var pcs [maxStackDepth]uintptr
+	n := runtime.Callers(skip+2, pcs[:])
+	st := make(StackTrace, n)
+	for i := 0; i < n; i++ {
+		st[i] = Frame(pcs[i])
+	}
+	return st
+}
@@ -7,36 +7,40 @@ import (
 )
 
 var (
-	ErrSyntax = apperr.New("syntax_error",
+	ErrSyntax = apperr.Register(apperr.New("syntax_error",
 		apperr.WithTextTranslate(translate.Translate{translate.RU: "Неверный запрос"}),
 		apperr.WithCode(code.InvalidArgument),
-	)
-	ErrValidation = apperr.New("validation_error",
+	))
+	ErrValidation = apperr.Register(apperr.New("validation_error",
 		apperr.WithTextTranslate(translate.Translate{translate.RU: "Неверный запрос"}),
 		apperr.WithCode(code.InvalidArgument),
-	)
-	ErrEmptyData = apperr.New("empty_data_error",
+	))
+	ErrEmptyData = apperr.Register(apperr.New("empty_data_error",
 		apperr.WithTextTranslate(translate.Translate{translate.RU: "Неверный запрос"}),
 		apperr.WithCode(code.InvalidArgument),
-	)
-	ErrInternal = apperr.New("internal_error",
+	))
+	ErrInternal = apperr.Register(apperr.New("internal_error",
 		apperr.WithTextTranslate(translate.Translate{translate.RU: "Ошибка сервера"}),
 		apperr.WithCode(code.Internal),
-	)
-	ErrForbidden = apperr.New("forbidden_error",
+	))
+	ErrForbidden = apperr.Register(apperr.New("forbidden_error",
 		apperr.WithTextTranslate(translate.Translate{translate.RU: "Нет доступа"}),
 		apperr.WithCode(code.PermissionDenied),
-	)
-	ErrUnauthenticated = apperr.New("unauthenticated_error",
+	))
+	ErrUnauthenticated = apperr.Register(apperr.New("unauthenticated_error",
 		apperr.WithTextTranslate(translate.Translate{translate.RU: "Ошибка аутентификации"}),
 		apperr.WithCode(code.Unauthenticated),
-	)
-	ErrNotFound = apperr.New("not_found_error",
+	))
+	ErrNotFound = apperr.Register(apperr.New("not_found_error",
 		apperr.WithTextTranslate(translate.Translate{translate.RU: "Не найдено"}),
 		apperr.WithCode(code.NotFound),
-	)
-	ErrServerIsNotAvailable = apperr.New("server_is_not_available",
+	))
+	ErrServerIsNotAvailable = apperr.Register(apperr.New("server_is_not_available",
 		apperr.WithTextTranslate(translate.Translate{translate.RU: "Сервер недоступен"}),
 		apperr.WithCode(code.Unavailable),
-	)
+	))
+	ErrInternalMethod = apperr.Register(apperr.New("all",
+		apperr.WithTitleTranslate(translate.Translate{translate.RU: "Ошибка"}),
+		apperr.WithContext("all"),
+	))
 )
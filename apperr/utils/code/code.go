@@ -24,43 +24,28 @@ const (
 	Unauthenticated    Code = 16
 )
 
+// CustomCodeStart is the first value free for domain codes registered with Register.
+const CustomCodeStart Code = 1000
+
 func (c Code) String() string {
-	switch c {
-	case OK:
-		return "OK"
-	case Canceled:
-		return "Canceled"
-	case Unknown:
-		return "Unknown"
-	case InvalidArgument:
-		return "InvalidArgument"
-	case DeadlineExceeded:
-		return "DeadlineExceeded"
-	case NotFound:
-		return "NotFound"
-	case AlreadyExists:
-		return "AlreadyExists"
-	case PermissionDenied:
-		return "PermissionDenied"
-	case ResourceExhausted:
-		return "ResourceExhausted"
-	case FailedPrecondition:
-		return "FailedPrecondition"
-	case Aborted:
-		return "Aborted"
-	case OutOfRange:
-		return "OutOfRange"
-	case Unimplemented:
-		return "Unimplemented"
-	case Internal:
-		return "Internal"
-	case Unavailable:
-		return "Unavailable"
-	case DataLoss:
-		return "DataLoss"
-	case Unauthenticated:
-		return "Unauthenticated"
-	default:
-		return "Code(" + strconv.FormatInt(int64(c), 10) + ")"
+	if m, ok := Lookup(c); ok && m.Name != "" {
+		return m.Name
+	}
+	return "Code(" + strconv.FormatInt(int64(c), 10) + ")"
+}
+
+// Parse returns the code whose String is s, the lowest one if several codes
+// share the name.
+func Parse(s string) (Code, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	found := false
+	code := Unknown
+	for c, m := range mappings {
+		if m.Name == s && (!found || c < code) {
+			code, found = c, true
+		}
 	}
+	return code, found
 }
@@ -0,0 +1,61 @@
+package code
+
+import (
+	"maps"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+)
+
+// restoreMappings restores the global mappings when t ends.
+func restoreMappings(t *testing.T) {
+	mu.RLock()
+	saved, savedHttp, savedGrpc := maps.Clone(mappings), maps.Clone(httpCodes), maps.Clone(grpcCodes)
+	mu.RUnlock()
+
+	t.Cleanup(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		mappings, httpCodes, grpcCodes = saved, savedHttp, savedGrpc
+	})
+}
+
+func TestMapping(t *testing.T) {
+	restoreMappings(t)
+
+	assert.Equal(t, http.StatusNotFound, CodeToHttp(NotFound))
+	assert.Equal(t, http.StatusInternalServerError, CodeToHttp(OK))
+	assert.Equal(t, http.StatusInternalServerError, CodeToHttp(Code(999)))
+	assert.Equal(t, codes.Unknown, CodeToGrpc(OK))
+	assert.Equal(t, codes.NotFound, CodeToGrpc(NotFound))
+	assert.Equal(t, InvalidArgument, HttpToCode(http.StatusUnprocessableEntity))
+	assert.Equal(t, Internal, HttpToCode(http.StatusTeapot))
+	assert.Equal(t, "Code(999)", Code(999).String())
+
+	const Validation = CustomCodeStart
+	Register(Validation, Mapping{Name: "Validation", Http: http.StatusUnprocessableEntity, Grpc: codes.InvalidArgument})
+	RegisterHttp(http.StatusUnprocessableEntity, Validation)
+
+	assert.Equal(t, "Validation", Validation.String())
+	assert.Equal(t, http.StatusUnprocessableEntity, CodeToHttp(Validation))
+	assert.Equal(t, codes.InvalidArgument, CodeToGrpc(Validation))
+	assert.Equal(t, Validation, HttpToCode(http.StatusUnprocessableEntity))
+	assert.Equal(t, InvalidArgument, GrpcToCode(codes.InvalidArgument))
+	c, ok := Parse("Validation")
+	assert.True(t, ok)
+	assert.Equal(t, Validation, c)
+
+	Register(CustomCodeStart+1, Mapping{Name: "Validation"})
+	c, ok = Parse("Validation")
+	assert.True(t, ok)
+	assert.Equal(t, Validation, c)
+
+	SetHttp(FailedPrecondition, http.StatusPreconditionFailed)
+	assert.Equal(t, http.StatusPreconditionFailed, CodeToHttp(FailedPrecondition))
+
+	Register(Unknown, Mapping{Name: "Unknown"})
+	assert.Equal(t, http.StatusInternalServerError, CodeToHttp(Code(999)))
+	assert.Equal(t, codes.Unknown, CodeToGrpc(Code(999)))
+}
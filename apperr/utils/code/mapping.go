@@ -0,0 +1,129 @@
+package code
+
+import (
+	"net/http"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+)
+
+// Mapping describes how a code is named and sent over HTTP and gRPC.
+type Mapping struct {
+	Name string
+	Http int
+	Grpc codes.Code
+}
+
+var (
+	mu sync.RWMutex
+
+	mappings = map[Code]Mapping{
+		// OK is not an error, errors without a code are sent as Unknown.
+		OK:                 {Name: "OK"},
+		Canceled:           {Name: "Canceled", Http: 499, Grpc: codes.Canceled},
+		Unknown:            {Name: "Unknown", Http: http.StatusInternalServerError, Grpc: codes.Unknown},
+		InvalidArgument:    {Name: "InvalidArgument", Http: http.StatusBadRequest, Grpc: codes.InvalidArgument},
+		DeadlineExceeded:   {Name: "DeadlineExceeded", Http: http.StatusGatewayTimeout, Grpc: codes.DeadlineExceeded},
+		NotFound:           {Name: "NotFound", Http: http.StatusNotFound, Grpc: codes.NotFound},
+		AlreadyExists:      {Name: "AlreadyExists", Http: http.StatusConflict, Grpc: codes.AlreadyExists},
+		PermissionDenied:   {Name: "PermissionDenied", Http: http.StatusForbidden, Grpc: codes.PermissionDenied},
+		ResourceExhausted:  {Name: "ResourceExhausted", Http: http.StatusTooManyRequests, Grpc: codes.ResourceExhausted},
+		FailedPrecondition: {Name: "FailedPrecondition", Http: http.StatusBadRequest, Grpc: codes.FailedPrecondition},
+		Aborted:            {Name: "Aborted", Http: http.StatusConflict, Grpc: codes.Aborted},
+		OutOfRange:         {Name: "OutOfRange", Http: http.StatusBadRequest, Grpc: codes.OutOfRange},
+		Unimplemented:      {Name: "Unimplemented", Http: http.StatusNotImplemented, Grpc: codes.Unimplemented},
+		Internal:           {Name: "Internal", Http: http.StatusInternalServerError, Grpc: codes.Internal},
+		Unavailable:        {Name: "Unavailable", Http: http.StatusServiceUnavailable, Grpc: codes.Unavailable},
+		DataLoss:           {Name: "DataLoss", Http: http.StatusInternalServerError, Grpc: codes.DataLoss},
+		Unauthenticated:    {Name: "Unauthenticated", Http: http.StatusUnauthorized, Grpc: codes.Unauthenticated},
+	}
+
+	httpCodes = map[int]Code{
+		499:                                     Canceled,
+		http.StatusBadRequest:                   InvalidArgument,
+		http.StatusUnauthorized:                 Unauthenticated,
+		http.StatusForbidden:                    PermissionDenied,
+		http.StatusNotFound:                     NotFound,
+		http.StatusMethodNotAllowed:             Unimplemented,
+		http.StatusRequestTimeout:               DeadlineExceeded,
+		http.StatusConflict:                     AlreadyExists,
+		http.StatusPreconditionFailed:           FailedPrecondition,
+		http.StatusRequestedRangeNotSatisfiable: OutOfRange,
+		http.StatusUnprocessableEntity:          InvalidArgument,
+		http.StatusTooManyRequests:              ResourceExhausted,
+		http.StatusInternalServerError:          Internal,
+		http.StatusNotImplemented:               Unimplemented,
+		http.StatusBadGateway:                   Unavailable,
+		http.StatusServiceUnavailable:           Unavailable,
+		http.StatusGatewayTimeout:               DeadlineExceeded,
+	}
+
+	grpcCodes = map[codes.Code]Code{
+		codes.OK:                 OK,
+		codes.Canceled:           Canceled,
+		codes.Unknown:            Unknown,
+		codes.InvalidArgument:    InvalidArgument,
+		codes.DeadlineExceeded:   DeadlineExceeded,
+		codes.NotFound:           NotFound,
+		codes.AlreadyExists:      AlreadyExists,
+		codes.PermissionDenied:   PermissionDenied,
+		codes.ResourceExhausted:  ResourceExhausted,
+		codes.FailedPrecondition: FailedPrecondition,
+		codes.Aborted:            Aborted,
+		codes.OutOfRange:         OutOfRange,
+		codes.Unimplemented:      Unimplemented,
+		codes.Internal:           Internal,
+		codes.Unavailable:        Unavailable,
+		codes.DataLoss:           DataLoss,
+		codes.Unauthenticated:    Unauthenticated,
+	}
+)
+
+// Register adds or overrides the mapping of c. Custom codes should start
+// at CustomCodeStart. The HTTP status is mapped back to c by HttpToCode
+// unless it is mapped already, use RegisterHttp to override it.
+func Register(c Code, m Mapping) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	mappings[c] = m
+	if _, ok := httpCodes[m.Http]; !ok && m.Http != 0 {
+		httpCodes[m.Http] = c
+	}
+}
+
+// SetHttp overrides the HTTP status of c, e.g. SetHttp(InvalidArgument, 422).
+func SetHttp(c Code, status int) {
+	m, _ := Lookup(c)
+	m.Http = status
+	Register(c, m)
+}
+
+// SetGrpc overrides the gRPC code of c.
+func SetGrpc(c Code, grpcCode codes.Code) {
+	m, _ := Lookup(c)
+	m.Grpc = grpcCode
+	Register(c, m)
+}
+
+// RegisterHttp sets the code HttpToCode returns for status.
+func RegisterHttp(status int, c Code) {
+	mu.Lock()
+	defer mu.Unlock()
+	httpCodes[status] = c
+}
+
+// RegisterGrpc sets the code GrpcToCode returns for grpcCode.
+func RegisterGrpc(grpcCode codes.Code, c Code) {
+	mu.Lock()
+	defer mu.Unlock()
+	grpcCodes[grpcCode] = c
+}
+
+// Lookup returns the mapping of c.
+func Lookup(c Code) (Mapping, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	m, ok := mappings[c]
+	return m, ok
+}
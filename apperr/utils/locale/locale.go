@@ -0,0 +1,174 @@
+package locale
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"sync"
+
+	"github.com/c2pc/go-pkg/apperr/utils/translate"
+	"golang.org/x/text/language"
+	"google.golang.org/grpc/metadata"
+)
+
+const HeaderAcceptLanguage = "Accept-Language"
+
+const metadataAcceptLanguage = "accept-language"
+
+type languageKey struct{}
+
+// Negotiator matches Accept-Language values against the supported languages.
+// The first supported language is the default one.
+type Negotiator struct {
+	mu        sync.RWMutex
+	supported []string
+	matcher   language.Matcher
+	// registered makes the negotiator follow translate.Languages.
+	registered bool
+}
+
+func NewNegotiator(supported ...string) *Negotiator {
+	n := &Negotiator{}
+	n.SetSupported(supported...)
+	return n
+}
+
+// newRegisteredNegotiator returns a negotiator supporting the languages
+// registered in translate until SetSupported is called.
+func newRegisteredNegotiator() *Negotiator {
+	n := &Negotiator{registered: true}
+	n.set(registeredLanguages())
+	translate.OnLanguagesChange(n.refresh)
+	return n
+}
+
+// refresh catches up with translate.Languages unless SetSupported was called.
+func (n *Negotiator) refresh() {
+	langs := registeredLanguages()
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.registered {
+		n.set(langs)
+	}
+}
+
+func registeredLanguages() []string {
+	langs := translate.Languages()
+	supported := make([]string, 0, len(langs))
+	for _, lang := range langs {
+		supported = append(supported, string(lang))
+	}
+	return supported
+}
+
+// SetSupported replaces the supported languages, the first one is the default.
+func (n *Negotiator) SetSupported(supported ...string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.registered = false
+	n.set(supported)
+}
+
+// set replaces the supported languages, n.mu must be held.
+func (n *Negotiator) set(supported []string) {
+	tags := make([]language.Tag, 0, len(supported))
+	bases := make([]string, 0, len(supported))
+	for _, s := range supported {
+		tag, err := language.Parse(s)
+		if err != nil {
+			continue
+		}
+		tags = append(tags, tag)
+		base, _ := tag.Base()
+		if !slices.Contains(bases, base.String()) {
+			bases = append(bases, base.String())
+		}
+	}
+	if len(tags) == 0 {
+		tags = []language.Tag{language.Russian}
+		bases = []string{"ru"}
+	}
+
+	n.supported = bases
+	n.matcher = language.NewMatcher(tags)
+}
+
+func (n *Negotiator) state() ([]string, language.Matcher) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.supported, n.matcher
+}
+
+func (n *Negotiator) Supported() []string {
+	supported, _ := n.state()
+	return append([]string(nil), supported...)
+}
+
+func (n *Negotiator) Default() string {
+	supported, _ := n.state()
+	return supported[0]
+}
+
+// Match returns the base of the best supported language for the Accept-Language values.
+func (n *Negotiator) Match(acceptLanguage ...string) string {
+	_, matcher := n.state()
+
+	tag, _ := language.MatchStrings(matcher, acceptLanguage...)
+	base, _ := tag.Base()
+	return base.String()
+}
+
+// FromRequest returns the language of the context override or of the Accept-Language header.
+func (n *Negotiator) FromRequest(r *http.Request) string {
+	if lang, ok := FromContext(r.Context()); ok {
+		return n.Match(lang)
+	}
+	return n.Match(r.Header.Get(HeaderAcceptLanguage))
+}
+
+// FromIncomingContext returns the language of the context override or of the
+// accept-language incoming gRPC metadata.
+func (n *Negotiator) FromIncomingContext(ctx context.Context) string {
+	if lang, ok := FromContext(ctx); ok {
+		return n.Match(lang)
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(metadataAcceptLanguage); len(values) > 0 {
+			return n.Match(values...)
+		}
+	}
+	return n.Default()
+}
+
+// WithLanguage stores an explicit language, e.g. from user settings,
+// that takes precedence over Accept-Language.
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, languageKey{}, lang)
+}
+
+func FromContext(ctx context.Context) (string, bool) {
+	lang, ok := ctx.Value(languageKey{}).(string)
+	return lang, ok && lang != ""
+}
+
+// Default is the negotiator used by httperr and grpcerr. It supports the
+// languages registered in translate, e.g. by loading a bundle, with the
+// default one first, until SetSupported fixes the list.
+var Default = newRegisteredNegotiator()
+
+func SetSupported(supported ...string) {
+	Default.SetSupported(supported...)
+}
+
+func Match(acceptLanguage ...string) string {
+	return Default.Match(acceptLanguage...)
+}
+
+func FromRequest(r *http.Request) string {
+	return Default.FromRequest(r)
+}
+
+func FromIncomingContext(ctx context.Context) string {
+	return Default.FromIncomingContext(ctx)
+}
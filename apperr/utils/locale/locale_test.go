@@ -0,0 +1,57 @@
+package locale
+
+import (
+	"context"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/c2pc/go-pkg/apperr/utils/translate"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestNegotiator(t *testing.T) {
+	n := NewNegotiator("ru-RU", "ru", "en", "kk")
+
+	assert.Equal(t, "ru", n.Default())
+	assert.Equal(t, []string{"ru", "en", "kk"}, n.Supported())
+	assert.Equal(t, "ru", n.Match(""))
+	assert.Equal(t, "en", n.Match("en-US,en;q=0.9"))
+	assert.Equal(t, "kk", n.Match("de;q=0.9,kk;q=0.8"))
+	assert.Equal(t, "ru", n.Match("de"))
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set(HeaderAcceptLanguage, "en")
+	assert.Equal(t, "en", n.FromRequest(r))
+	assert.Equal(t, "kk", n.FromRequest(r.WithContext(WithLanguage(r.Context(), "kk"))))
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("accept-language", "en-GB"))
+	assert.Equal(t, "en", n.FromIncomingContext(ctx))
+	assert.Equal(t, "kk", n.FromIncomingContext(WithLanguage(ctx, "kk")))
+	assert.Equal(t, "ru", n.FromIncomingContext(context.Background()))
+}
+
+func TestRegisteredNegotiator(t *testing.T) {
+	var lang translate.Language
+	for _, l := range []translate.Language{"kk", "uz", "ky", "tg", "hy"} {
+		if !slices.Contains(translate.Languages(), l) {
+			lang = l
+			break
+		}
+	}
+	if lang == "" {
+		t.Skip("every candidate language is registered")
+	}
+
+	n := newRegisteredNegotiator()
+	assert.Equal(t, "ru", n.Default())
+	assert.Equal(t, "en", n.Match("en-US"))
+	assert.Equal(t, "ru", n.Match(string(lang)))
+
+	translate.RegisterLanguage(lang)
+	assert.Equal(t, string(lang), n.Match(string(lang)+";q=0.9,en;q=0.8"))
+
+	n.SetSupported("en")
+	assert.Equal(t, "en", n.Match(string(lang)))
+}
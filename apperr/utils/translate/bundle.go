@@ -0,0 +1,178 @@
+package translate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultBundle is consulted by apperr.Translate for errors without inline translations.
+var DefaultBundle = NewBundle()
+
+// Message is the translated title and text of an error ID.
+type Message struct {
+	Title string `json:"title" yaml:"title"`
+	Text  string `json:"text" yaml:"text"`
+	// Language the message was found in by Lookup.
+	Language Language `json:"-" yaml:"-"`
+}
+
+func (m *Message) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		m.Text = text
+		return nil
+	}
+
+	type message Message
+	return json.Unmarshal(data, (*message)(m))
+}
+
+func (m *Message) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		m.Text = value.Value
+		return nil
+	}
+
+	type message Message
+	return value.Decode((*message)(m))
+}
+
+// Bundle holds messages keyed by language and error ID.
+type Bundle struct {
+	mu       sync.RWMutex
+	messages map[Language]map[string]Message
+}
+
+func NewBundle() *Bundle {
+	return &Bundle{messages: map[Language]map[string]Message{}}
+}
+
+// AddMessages adds messages of lang to the bundle. Languages of
+// DefaultBundle are registered with RegisterLanguage, other bundles keep
+// theirs to themselves, see Languages.
+func (b *Bundle) AddMessages(lang Language, messages map[string]Message) {
+	if b == DefaultBundle {
+		RegisterLanguage(lang)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.messages[lang] == nil {
+		b.messages[lang] = map[string]Message{}
+	}
+	for id, msg := range messages {
+		b.messages[lang][id] = msg
+	}
+}
+
+// Load parses a YAML or JSON document of messages of lang, keyed by error ID.
+// A value is either the text or an object with title and text.
+func (b *Bundle) Load(lang Language, ext string, data []byte) error {
+	messages := map[string]Message{}
+
+	switch strings.ToLower(ext) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &messages); err != nil {
+			return err
+		}
+	case ".json":
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("translate: unsupported bundle format %q", ext)
+	}
+
+	b.AddMessages(lang, messages)
+	return nil
+}
+
+// LoadFile loads a bundle file, its name without extension is the language, e.g. "kk.yaml".
+func (b *Bundle) LoadFile(name string) error {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+
+	ext := path.Ext(name)
+	return b.Load(Language(strings.TrimSuffix(path.Base(name), ext)), ext, data)
+}
+
+// LoadFS loads every YAML and JSON bundle of dir in fsys, e.g. an embed.FS.
+func (b *Bundle) LoadFS(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		ext := strings.ToLower(path.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml" && ext != ".json") {
+			continue
+		}
+
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		if err := b.Load(Language(strings.TrimSuffix(entry.Name(), path.Ext(entry.Name()))), ext, data); err != nil {
+			return fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// LoadDir loads every YAML and JSON bundle of a directory.
+func (b *Bundle) LoadDir(dir string) error {
+	return b.LoadFS(os.DirFS(dir), ".")
+}
+
+// Languages returns the languages of the bundle sorted.
+func (b *Bundle) Languages() []Language {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	langs := make([]Language, 0, len(b.messages))
+	for lang := range b.messages {
+		langs = append(langs, lang)
+	}
+	sort.Slice(langs, func(i, j int) bool { return langs[i] < langs[j] })
+	return langs
+}
+
+// Lookup returns the message of id for lang, walking its fallback chain.
+func (b *Bundle) Lookup(lang string, id string) (Message, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, l := range Chain(Language(lang)) {
+		if msg, ok := b.messages[l][id]; ok {
+			msg.Language = l
+			return msg, true
+		}
+	}
+
+	return Message{}, false
+}
+
+func LoadDir(dir string) error {
+	return DefaultBundle.LoadDir(dir)
+}
+
+func LoadFS(fsys fs.FS, dir string) error {
+	return DefaultBundle.LoadFS(fsys, dir)
+}
+
+func Lookup(lang string, id string) (Message, bool) {
+	return DefaultBundle.Lookup(lang, id)
+}
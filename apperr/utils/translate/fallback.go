@@ -0,0 +1,126 @@
+package translate
+
+import (
+	"sort"
+	"sync"
+)
+
+var (
+	languagesMu     sync.RWMutex
+	languages       = map[Language]bool{RU: true, EN: true}
+	fallbacks       = map[Language][]Language{}
+	defaultLanguage = RU
+
+	listenersMu sync.Mutex
+	listeners   []func()
+)
+
+// OnLanguagesChange registers f to be called after a language is registered
+// or the default language changes.
+func OnLanguagesChange(f func()) {
+	listenersMu.Lock()
+	defer listenersMu.Unlock()
+	listeners = append(listeners, f)
+}
+
+func notifyLanguagesChange() {
+	listenersMu.Lock()
+	fs := append([]func(){}, listeners...)
+	listenersMu.Unlock()
+
+	for _, f := range fs {
+		f()
+	}
+}
+
+// RegisterLanguage adds languages to the list of supported ones.
+func RegisterLanguage(langs ...Language) {
+	languagesMu.Lock()
+	changed := false
+	for _, lang := range langs {
+		if lang != "" && !languages[lang] {
+			languages[lang] = true
+			changed = true
+		}
+	}
+	languagesMu.Unlock()
+
+	if changed {
+		notifyLanguagesChange()
+	}
+}
+
+// Languages returns every registered language sorted, the default one first.
+func Languages() []Language {
+	languagesMu.RLock()
+	defer languagesMu.RUnlock()
+
+	langs := make([]Language, 0, len(languages))
+	for lang := range languages {
+		if lang != defaultLanguage {
+			langs = append(langs, lang)
+		}
+	}
+	sort.Slice(langs, func(i, j int) bool { return langs[i] < langs[j] })
+
+	return append([]Language{defaultLanguage}, langs...)
+}
+
+// SetDefaultLanguage sets the last language of every fallback chain, RU by default.
+func SetDefaultLanguage(lang Language) {
+	RegisterLanguage(lang)
+
+	languagesMu.Lock()
+	changed := defaultLanguage != lang
+	defaultLanguage = lang
+	languagesMu.Unlock()
+
+	if changed {
+		notifyLanguagesChange()
+	}
+}
+
+func DefaultLanguage() Language {
+	languagesMu.RLock()
+	defer languagesMu.RUnlock()
+	return defaultLanguage
+}
+
+// SetFallback configures the chain used when a translation for chain[0] is
+// missing, SetFallback("kk", RU, EN) means kk -> ru -> en. SetFallback("kk")
+// removes the chain of kk. It does not register the languages.
+func SetFallback(chain ...Language) {
+	if len(chain) == 0 {
+		return
+	}
+	if len(chain) == 1 {
+		languagesMu.Lock()
+		defer languagesMu.Unlock()
+		delete(fallbacks, chain[0])
+		return
+	}
+
+	languagesMu.Lock()
+	defer languagesMu.Unlock()
+	fallbacks[chain[0]] = append([]Language(nil), chain[1:]...)
+}
+
+// Chain returns lang, its fallbacks and the default language without duplicates.
+func Chain(lang Language) []Language {
+	languagesMu.RLock()
+	defer languagesMu.RUnlock()
+
+	chain := []Language{lang}
+	seen := map[Language]bool{lang: true}
+	for _, l := range fallbacks[lang] {
+		if !seen[l] {
+			chain = append(chain, l)
+			seen[l] = true
+		}
+	}
+	if !seen[defaultLanguage] {
+		chain = append(chain, defaultLanguage)
+	}
+
+	return chain
+}
@@ -0,0 +1,427 @@
+package translate
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// FormatNamed formats an ICU-style message with named arguments:
+//
+//	{name}                                        the value of name
+//	{count, number}                               the value of count
+//	{count, plural, one {# попытка} few {# попытки} many {# попыток} other {# попытки}}
+//	{gender, select, male {он} female {она} other {они}}
+//
+// In plural branches # is the number and =N matches it exactly. Quote
+// braces with apostrophes, a doubled apostrophe is a single one. The message is
+// returned as is when it cannot be parsed.
+func FormatNamed(lang Language, message string, args map[string]any) string {
+	nodes, err := parseMessage(message)
+	if err != nil {
+		return message
+	}
+
+	var b strings.Builder
+	formatNodes(&b, lang, nodes, args, nil)
+	return b.String()
+}
+
+// ArgNames returns the argument names used by an ICU-style message in order of appearance.
+func ArgNames(message string) ([]string, error) {
+	nodes, err := parseMessage(message)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	seen := map[string]bool{}
+	var walk func(nodes []node)
+	walk = func(nodes []node) {
+		for _, n := range nodes {
+			if n.name != "" && !seen[n.name] {
+				seen[n.name] = true
+				names = append(names, n.name)
+			}
+			for _, c := range n.cases {
+				walk(c.nodes)
+			}
+		}
+	}
+	walk(nodes)
+
+	return names, nil
+}
+
+type nodeKind int
+
+const (
+	textNode nodeKind = iota
+	argNode
+	hashNode
+	pluralNode
+	selectNode
+)
+
+type node struct {
+	kind   nodeKind
+	text   string
+	name   string
+	offset float64
+	cases  []pluralCase
+}
+
+type pluralCase struct {
+	key   string
+	nodes []node
+}
+
+var errSyntax = errors.New("translate: invalid message syntax")
+
+type parser struct {
+	src []rune
+	pos int
+}
+
+func parseMessage(message string) ([]node, error) {
+	p := &parser{src: []rune(message)}
+	nodes, err := p.message(0, false)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.src) {
+		return nil, errSyntax
+	}
+	return nodes, nil
+}
+
+func (p *parser) message(depth int, inPlural bool) ([]node, error) {
+	var nodes []node
+	var text strings.Builder
+
+	flush := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, node{kind: textNode, text: text.String()})
+			text.Reset()
+		}
+	}
+
+	for p.pos < len(p.src) {
+		ch := p.src[p.pos]
+		switch {
+		case ch == '{':
+			flush()
+			n, err := p.argument(depth, inPlural)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, n)
+		case ch == '}':
+			if depth == 0 {
+				return nil, errSyntax
+			}
+			flush()
+			return nodes, nil
+		case ch == '#' && inPlural:
+			flush()
+			nodes = append(nodes, node{kind: hashNode})
+			p.pos++
+		case ch == '\'':
+			p.pos++
+			p.quoted(&text)
+		default:
+			text.WriteRune(ch)
+			p.pos++
+		}
+	}
+
+	if depth > 0 {
+		return nil, errSyntax
+	}
+	flush()
+	return nodes, nil
+}
+
+// quoted handles the text after an apostrophe.
+func (p *parser) quoted(text *strings.Builder) {
+	if p.pos < len(p.src) && p.src[p.pos] == '\'' {
+		text.WriteRune('\'')
+		p.pos++
+		return
+	}
+	if p.pos >= len(p.src) || !strings.ContainsRune("{}#", p.src[p.pos]) {
+		text.WriteRune('\'')
+		return
+	}
+
+	for p.pos < len(p.src) {
+		ch := p.src[p.pos]
+		p.pos++
+		if ch == '\'' {
+			if p.pos < len(p.src) && p.src[p.pos] == '\'' {
+				text.WriteRune('\'')
+				p.pos++
+				continue
+			}
+			return
+		}
+		text.WriteRune(ch)
+	}
+}
+
+func (p *parser) argument(depth int, inPlural bool) (node, error) {
+	p.pos++ // {
+	p.spaces()
+
+	name := p.word()
+	if name == "" {
+		return node{}, errSyntax
+	}
+	p.spaces()
+
+	if p.consume('}') {
+		return node{kind: argNode, name: name}, nil
+	}
+	if !p.consume(',') {
+		return node{}, errSyntax
+	}
+	p.spaces()
+
+	typ := p.word()
+	p.spaces()
+
+	switch typ {
+	case "number":
+		if p.consume(',') {
+			p.spaces()
+			p.word()
+			p.spaces()
+		}
+		if !p.consume('}') {
+			return node{}, errSyntax
+		}
+		return node{kind: argNode, name: name}, nil
+	case "plural", "select":
+		if !p.consume(',') {
+			return node{}, errSyntax
+		}
+	default:
+		return node{}, errSyntax
+	}
+
+	n := node{kind: selectNode, name: name}
+	if typ == "plural" {
+		n.kind = pluralNode
+	}
+
+	for {
+		p.spaces()
+		if p.consume('}') {
+			break
+		}
+
+		key := p.selector()
+		if key == "" {
+			return node{}, errSyntax
+		}
+		if n.kind == pluralNode && strings.HasPrefix(key, "offset:") {
+			offset, err := strconv.ParseFloat(strings.TrimPrefix(key, "offset:"), 64)
+			if err != nil {
+				return node{}, errSyntax
+			}
+			n.offset = offset
+			continue
+		}
+
+		p.spaces()
+		if !p.consume('{') {
+			return node{}, errSyntax
+		}
+		nodes, err := p.message(depth+1, inPlural || n.kind == pluralNode)
+		if err != nil {
+			return node{}, err
+		}
+		if !p.consume('}') {
+			return node{}, errSyntax
+		}
+		n.cases = append(n.cases, pluralCase{key: key, nodes: nodes})
+	}
+
+	return n, nil
+}
+
+func (p *parser) spaces() {
+	for p.pos < len(p.src) && unicode.IsSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *parser) consume(ch rune) bool {
+	if p.pos < len(p.src) && p.src[p.pos] == ch {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) word() string {
+	start := p.pos
+	for p.pos < len(p.src) && (unicode.IsLetter(p.src[p.pos]) || unicode.IsDigit(p.src[p.pos]) || p.src[p.pos] == '_') {
+		p.pos++
+	}
+	return string(p.src[start:p.pos])
+}
+
+func (p *parser) selector() string {
+	start := p.pos
+	for p.pos < len(p.src) && !unicode.IsSpace(p.src[p.pos]) && p.src[p.pos] != '{' && p.src[p.pos] != '}' {
+		p.pos++
+	}
+	return string(p.src[start:p.pos])
+}
+
+func formatNodes(b *strings.Builder, lang Language, nodes []node, args map[string]any, number *float64) {
+	for _, n := range nodes {
+		switch n.kind {
+		case textNode:
+			b.WriteString(n.text)
+		case argNode:
+			b.WriteString(fmt.Sprint(args[n.name]))
+		case hashNode:
+			if number != nil {
+				b.WriteString(strconv.FormatFloat(*number, 'f', -1, 64))
+			} else {
+				b.WriteRune('#')
+			}
+		case selectNode:
+			key := fmt.Sprint(args[n.name])
+			if c, ok := findCase(n.cases, key); ok {
+				formatNodes(b, lang, c.nodes, args, number)
+			} else if c, ok := findCase(n.cases, "other"); ok {
+				formatNodes(b, lang, c.nodes, args, number)
+			}
+		case pluralNode:
+			value, ok := toFloat(args[n.name])
+			if !ok {
+				b.WriteString(fmt.Sprint(args[n.name]))
+				continue
+			}
+			exact := "=" + strconv.FormatFloat(value, 'f', -1, 64)
+			v := value - n.offset
+
+			c, found := findCase(n.cases, exact)
+			if !found {
+				c, found = findCase(n.cases, PluralCategory(lang, v))
+			}
+			if !found {
+				c, found = findCase(n.cases, "other")
+			}
+			if found {
+				formatNodes(b, lang, c.nodes, args, &v)
+			}
+		}
+	}
+}
+
+func findCase(cases []pluralCase, key string) (pluralCase, bool) {
+	for _, c := range cases {
+		if c.key == key {
+			return c, true
+		}
+	}
+	return pluralCase{}, false
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// PluralRule returns the CLDR plural category (zero, one, two, few, many, other) of n.
+type PluralRule func(n float64) string
+
+var pluralRules = map[Language]PluralRule{
+	RU:   slavicPlural,
+	EN:   oneOtherPlural,
+	"uk": slavicPlural,
+	"be": slavicPlural,
+	"kk": func(n float64) string {
+		if n == 1 {
+			return "one"
+		}
+		return "other"
+	},
+}
+
+// RegisterPluralRule sets the plural rule of lang, languages without one use the English rule.
+func RegisterPluralRule(lang Language, rule PluralRule) {
+	languagesMu.Lock()
+	defer languagesMu.Unlock()
+	pluralRules[lang] = rule
+}
+
+// PluralCategory returns the plural category of n in lang.
+func PluralCategory(lang Language, n float64) string {
+	languagesMu.RLock()
+	rule, ok := pluralRules[lang]
+	languagesMu.RUnlock()
+	if !ok {
+		rule = oneOtherPlural
+	}
+	return rule(n)
+}
+
+func oneOtherPlural(n float64) string {
+	if n == 1 {
+		return "one"
+	}
+	return "other"
+}
+
+func slavicPlural(n float64) string {
+	if n != math.Trunc(n) {
+		return "other"
+	}
+
+	i := int64(math.Abs(n))
+	switch {
+	case i%10 == 1 && i%100 != 11:
+		return "one"
+	case i%10 >= 2 && i%10 <= 4 && (i%100 < 12 || i%100 > 14):
+		return "few"
+	default:
+		return "many"
+	}
+}
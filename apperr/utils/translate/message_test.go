@@ -0,0 +1,42 @@
+package translate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatNamed(t *testing.T) {
+	ru := "{count, plural, one {осталась # попытка} few {осталось # попытки} many {осталось # попыток} other {осталось # попытки}}"
+	en := "{count, plural, =0 {no attempts left} one {# attempt left} other {# attempts left}}"
+
+	for n, expected := range map[int]string{1: "осталась 1 попытка", 3: "осталось 3 попытки", 5: "осталось 5 попыток", 11: "осталось 11 попыток", 21: "осталась 21 попытка", 22: "осталось 22 попытки"} {
+		assert.Equal(t, expected, FormatNamed(RU, ru, map[string]any{"count": n}))
+	}
+	assert.Equal(t, "осталось 1.5 попытки", FormatNamed(RU, ru, map[string]any{"count": 1.5}))
+
+	assert.Equal(t, "no attempts left", FormatNamed(EN, en, map[string]any{"count": 0}))
+	assert.Equal(t, "1 attempt left", FormatNamed(EN, en, map[string]any{"count": 1}))
+	assert.Equal(t, "2 attempts left", FormatNamed(EN, en, map[string]any{"count": 2}))
+
+	assert.Equal(t, "User admin: she is blocked", FormatNamed(EN, "User {login}: {gender, select, male {he} female {she} other {they}} is blocked", map[string]any{"login": "admin", "gender": "female"}))
+	assert.Equal(t, "{literal} it's 5", FormatNamed(EN, "'{literal}' it''s {n, number}", map[string]any{"n": 5}))
+	assert.Equal(t, "{broken", FormatNamed(EN, "{broken", nil))
+}
+
+func TestArgNames(t *testing.T) {
+	names, err := ArgNames("{login} has {count, plural, one {# {kind}} other {# {kind}s}}")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"login", "count", "kind"}, names)
+
+	_, err = ArgNames("{count, plural, one {x}")
+	assert.Error(t, err)
+}
+
+func TestTranslateNamed(t *testing.T) {
+	tr := Translate{RU: "Осталось {count, plural, one {# попытка} few {# попытки} other {# попыток}}"}
+
+	assert.Equal(t, "Осталось 2 попытки", tr.TranslateNamed("en", map[string]any{"count": 2}))
+	assert.Equal(t, "Пользователь %s", Translate{RU: "Пользователь %s"}.Translate("ru"))
+	assert.Equal(t, "Пользователь admin", Translate{RU: "Пользователь %s"}.Translate("ru", "admin"))
+}
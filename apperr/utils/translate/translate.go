@@ -6,22 +6,40 @@ type Language string
 
 const (
 	RU Language = "ru"
+	EN Language = "en"
 )
 
 type Translate map[Language]string
 
+// Translate returns the translation for acceptLang, walking its fallback chain
+// when it is missing.
 func (t Translate) Translate(acceptLang string, args ...any) string {
-	tr, found := t[Language(acceptLang)]
-	if !found {
-		tr, found = t[RU]
-		if !found {
-			return ""
+	for _, lang := range Chain(Language(acceptLang)) {
+		if tr, found := t[lang]; found {
+			return Format(tr, args...)
 		}
 	}
 
+	return ""
+}
+
+// TranslateNamed returns the translation for acceptLang formatted with FormatNamed,
+// plural rules are those of the language the translation was found in.
+func (t Translate) TranslateNamed(acceptLang string, args map[string]any) string {
+	for _, lang := range Chain(Language(acceptLang)) {
+		if tr, found := t[lang]; found {
+			return FormatNamed(lang, tr, args)
+		}
+	}
+
+	return ""
+}
+
+// Format applies args to the message, it is returned as is without args.
+func Format(message string, args ...any) string {
 	if len(args) > 0 {
-		return fmt.Sprintf(tr, args...)
+		return fmt.Sprintf(message, args...)
 	}
 
-	return tr
+	return message
 }
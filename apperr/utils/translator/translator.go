@@ -1,6 +1,8 @@
 package translator
 
 import (
+	"sync"
+
 	"github.com/go-playground/locales/en"
 	"github.com/go-playground/locales/ru"
 	ut "github.com/go-playground/universal-translator"
@@ -8,20 +10,40 @@ import (
 	rutranslations "github.com/go-playground/validator/v10/translations/ru"
 )
 
-var utrans *ut.UniversalTranslator
+var (
+	mu     sync.RWMutex
+	utrans *ut.UniversalTranslator
+)
 
-func SetValidateTranslators(validate *validator.Validate) {
+func newUniversalTranslator() *ut.UniversalTranslator {
 	en := en.New()
 	ru := ru.New()
-	utrans = ut.New(en, en, ru)
-	trans, _ := utrans.GetTranslator("ru")
+	return ut.New(en, en, ru)
+}
+
+func SetValidateTranslators(validate *validator.Validate) {
+	u := newUniversalTranslator()
+	trans, _ := u.GetTranslator("ru")
 	_ = rutranslations.RegisterDefaultTranslations(validate, trans)
+
+	mu.Lock()
+	defer mu.Unlock()
+	utrans = u
 }
 
+// GetTranslator returns the translator of the last validator passed to
+// SetValidateTranslators. Without one, messages stay untranslated.
 func GetTranslator(acceptLang string) ut.Translator {
-	t, found := utrans.FindTranslator(acceptLang)
+	mu.Lock()
+	if utrans == nil {
+		utrans = newUniversalTranslator()
+	}
+	u := utrans
+	mu.Unlock()
+
+	t, found := u.FindTranslator(acceptLang)
 	if !found {
-		t, _ = utrans.FindTranslator("ru")
+		t, _ = u.FindTranslator("ru")
 	}
 
 	return t
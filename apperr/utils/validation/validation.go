@@ -0,0 +1,99 @@
+package validation
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/c2pc/go-pkg/apperr"
+	"github.com/c2pc/go-pkg/apperr/utils/translator"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+)
+
+// Register sets up v to name fields by their `key` tag and to translate its messages.
+func Register(v *validator.Validate) {
+	v.RegisterTagNameFunc(RegisterTagNameFunc)
+	translator.SetValidateTranslators(v)
+}
+
+func RegisterTagNameFunc(fld reflect.StructField) string {
+	fieldName := fld.Tag.Get("key")
+	if fieldName == "-" {
+		return ""
+	}
+	return fieldName
+}
+
+// Violations returns one violation per failed field of err, nil if err is
+// not validator.ValidationErrors. Columns are paths like items[2].unit_price.
+func Violations(err error, trans ut.Translator) []apperr.Violation {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return nil
+	}
+
+	violations := make([]apperr.Violation, 0, len(validationErrors))
+	for _, fe := range validationErrors {
+		violations = append(violations, apperr.Violation{
+			Column: Path(fe.Namespace()),
+			Error:  message(fe, trans),
+			Tag:    fe.Tag(),
+			Param:  fe.Param(),
+		})
+	}
+	return violations
+}
+
+// message returns the message of fe without the leading field name, the
+// column already names the field.
+func message(fe validator.FieldError, trans ut.Translator) string {
+	msg := fe.Error()
+	if trans != nil {
+		msg = fe.Translate(trans)
+	}
+
+	msg = strings.TrimPrefix(msg, fe.Field()+" ")
+	return strings.Join(strings.Fields(msg), " ")
+}
+
+// Path converts a validator namespace such as CreateRequest.Items[2].UnitPrice
+// into items[2].unit_price, the top-level struct name is dropped.
+func Path(namespace string) string {
+	if i := strings.Index(namespace, "."); i != -1 {
+		namespace = namespace[i+1:]
+	}
+
+	segments := strings.Split(namespace, ".")
+	for i, segment := range segments {
+		name, index := segment, ""
+		if j := strings.Index(segment, "["); j != -1 {
+			name, index = segment[:j], segment[j:]
+		}
+		segments[i] = SnakeCase(name) + index
+	}
+	return strings.Join(segments, ".")
+}
+
+// SnakeCase converts a Go field name to snake_case keeping acronyms together: UserID -> user_id.
+func SnakeCase(s string) string {
+	runes := []rune(s)
+
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				prev := runes[i-1]
+				nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+				if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+					b.WriteRune('_')
+				}
+			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,64 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/c2pc/go-pkg/apperr"
+	"github.com/c2pc/go-pkg/apperr/utils/translator"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPath(t *testing.T) {
+	assert.Equal(t, "user_id", Path("Request.UserID"))
+	assert.Equal(t, "items[2].unit_price", Path("CreateOrder.Items[2].UnitPrice"))
+	assert.Equal(t, "http_status", SnakeCase("HTTPStatus"))
+	assert.Equal(t, "address2_line", SnakeCase("Address2Line"))
+}
+
+func TestViolations(t *testing.T) {
+	type item struct {
+		UnitPrice int `validate:"gt=0"`
+	}
+	type request struct {
+		Name  string `validate:"required"`
+		Items []item `validate:"dive"`
+	}
+
+	v := validator.New()
+	Register(v)
+
+	err := v.Struct(request{Items: []item{{UnitPrice: 1}, {UnitPrice: 0}}})
+
+	violations := Violations(err, translator.GetTranslator("ru"))
+	assert.Len(t, violations, 2)
+	assert.Equal(t, apperr.Violation{Column: "name", Error: "обязательное поле", Tag: "required"}, violations[0])
+	assert.Equal(t, "items[1].unit_price", violations[1].Column)
+	assert.Equal(t, "gt", violations[1].Tag)
+	assert.Equal(t, "0", violations[1].Param)
+	assert.NotContains(t, violations[1].Error, "UnitPrice")
+
+	type short struct {
+		Status string `key:"a" validate:"oneof=a_status b_status"`
+	}
+	violations = Violations(v.Struct(short{Status: "c"}), translator.GetTranslator("ru"))
+	assert.Len(t, violations, 1)
+	assert.Equal(t, "a", violations[0].Column)
+	assert.Contains(t, violations[0].Error, "[a_status b_status]")
+
+	assert.Nil(t, Violations(nil, nil))
+}
+
+func TestRegisterTwice(t *testing.T) {
+	type request struct {
+		Name string `key:"name" validate:"required"`
+	}
+
+	for i := 0; i < 2; i++ {
+		v := validator.New()
+		Register(v)
+
+		violations := Violations(v.Struct(request{}), translator.GetTranslator("ru"))
+		assert.Equal(t, []apperr.Violation{{Column: "name", Error: "обязательное поле", Tag: "required"}}, violations)
+	}
+}
@@ -0,0 +1,9 @@
+package apperr
+
+// Violation is a failed validation of a single request field.
+type Violation struct {
+	Column string `json:"column"`
+	Error  string `json:"error"`
+	Tag    string `json:"tag,omitempty"`
+	Param  string `json:"param,omitempty"`
+}
@@ -1,6 +1,10 @@
 package grpcerr
 
 import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
 	"github.com/c2pc/go-pkg/apperr"
 	"github.com/c2pc/go-pkg/apperr/utils/appErrors"
 	"github.com/c2pc/go-pkg/apperr/utils/code"
@@ -8,31 +12,135 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+// ParseError rebuilds an apperr.Error from a status produced by Response.
+// Statuses of services still sending every field as a BadRequest violation are read as well.
 func ParseError(err error) apperr.Error {
-	var id, text, title string
-
 	st := status.Convert(err)
 	grpcCode := code.GrpcToCode(st.Code())
 
+	var info *errdetails.ErrorInfo
+	var localized *errdetails.LocalizedMessage
+	var badRequest *errdetails.BadRequest
+	var retryInfo *errdetails.RetryInfo
+
 	for _, detail := range st.Details() {
 		switch t := detail.(type) {
+		case *errdetails.ErrorInfo:
+			info = t
+		case *errdetails.LocalizedMessage:
+			localized = t
 		case *errdetails.BadRequest:
-			for _, v := range t.GetFieldViolations() {
-				switch v.GetField() {
-				case "id":
-					id = v.GetDescription()
-				case "text":
-					text = v.GetDescription()
-				case "title":
-					title = v.GetDescription()
-				}
-			}
+			badRequest = t
+		case *errdetails.RetryInfo:
+			retryInfo = t
 		}
 	}
 
+	var annotators []apperr.Annotator
+	if info != nil && info.GetDomain() == Domain {
+		annotators = parseDetails(info, localized, badRequest)
+	} else {
+		annotators = parseLegacyDetails(badRequest)
+	}
+
+	retryAfter := retryInfo.GetRetryDelay().AsDuration()
+
 	if grpcCode == code.Unavailable || grpcCode == code.DeadlineExceeded {
-		return appErrors.ErrServerIsNotAvailable
+		return appErrors.ErrServerIsNotAvailable.WithRetryAfter(retryAfter)
+	}
+
+	annotators = append(annotators, apperr.WithCode(grpcCode), apperr.WithRetryAfter(retryAfter))
+
+	return apperr.New("", annotators...)
+}
+
+func parseDetails(info *errdetails.ErrorInfo, localized *errdetails.LocalizedMessage, badRequest *errdetails.BadRequest) []apperr.Annotator {
+	annotators := []apperr.Annotator{
+		apperr.WithID(info.GetReason()),
+		apperr.WithText(localized.GetMessage()),
 	}
 
-	return apperr.New(id, apperr.WithCode(grpcCode), apperr.WithText(text), apperr.WithTitle(title))
+	var fields apperr.Fields
+	var violations []apperr.Violation
+	for k, v := range info.GetMetadata() {
+		switch k {
+		case metaTitle:
+			annotators = append(annotators, apperr.WithTitle(v))
+		case metaContext:
+			annotators = append(annotators, apperr.WithContext(v))
+		case metaShowMessage:
+			showMessage, _ := strconv.ParseBool(v)
+			annotators = append(annotators, apperr.WithShowMessage(showMessage))
+		case metaItems:
+			annotators = append(annotators, apperr.WithErrors(parseItems(v)...))
+		case metaViolations:
+			_ = json.Unmarshal([]byte(v), &violations)
+		default:
+			if strings.HasPrefix(k, Domain+".") {
+				continue
+			}
+			if fields == nil {
+				fields = apperr.Fields{}
+			}
+			fields[k] = v
+		}
+	}
+	annotators = append(annotators, apperr.WithFields(fields))
+
+	if violations == nil {
+		for _, v := range badRequest.GetFieldViolations() {
+			violations = append(violations, apperr.Violation{Column: v.GetField(), Error: v.GetDescription()})
+		}
+	}
+	annotators = append(annotators, apperr.WithViolations(violations...))
+
+	return annotators
+}
+
+func parseLegacyDetails(badRequest *errdetails.BadRequest) []apperr.Annotator {
+	var annotators []apperr.Annotator
+
+	for _, v := range badRequest.GetFieldViolations() {
+		switch v.GetField() {
+		case "id":
+			annotators = append(annotators, apperr.WithID(v.GetDescription()))
+		case "text":
+			annotators = append(annotators, apperr.WithText(v.GetDescription()))
+		case "title":
+			annotators = append(annotators, apperr.WithTitle(v.GetDescription()))
+		case "context":
+			annotators = append(annotators, apperr.WithContext(v.GetDescription()))
+		case "show_message_banner":
+			showMessage, _ := strconv.ParseBool(v.GetDescription())
+			annotators = append(annotators, apperr.WithShowMessage(showMessage))
+		case "errors":
+			var violations []apperr.Violation
+			_ = json.Unmarshal([]byte(v.GetDescription()), &violations)
+			annotators = append(annotators, apperr.WithViolations(violations...))
+		case "items":
+			annotators = append(annotators, apperr.WithErrors(parseItems(v.GetDescription())...))
+		}
+	}
+
+	return annotators
+}
+
+func parseItems(s string) []apperr.Error {
+	var items []ErrorItem
+	if err := json.Unmarshal([]byte(s), &items); err != nil {
+		return nil
+	}
+
+	errs := make([]apperr.Error, 0, len(items))
+	for _, item := range items {
+		errs = append(errs, apperr.New(item.ID,
+			apperr.WithCode(code.GrpcToCode(item.Code)),
+			apperr.WithTitle(item.Title),
+			apperr.WithText(item.Text),
+			apperr.WithContext(item.Context),
+			apperr.WithShowMessage(item.ShowMessageBanner),
+			apperr.WithFields(item.Meta),
+		))
+	}
+	return errs
 }
@@ -0,0 +1,92 @@
+package grpcerr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/c2pc/go-pkg/apperr"
+	"github.com/c2pc/go-pkg/apperr/utils/code"
+	"github.com/c2pc/go-pkg/level"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestParseError(t *testing.T) {
+	err := apperr.New("method", apperr.WithTitle("Method"), apperr.WithContext("users")).WithError(
+		apperr.New("conflict",
+			apperr.WithCode(code.AlreadyExists),
+			apperr.WithText("Already exists"),
+			apperr.WithShowMessage(true),
+		).
+			WithField("user_id", 42).
+			WithViolations(apperr.Violation{Column: "login", Error: "is taken", Tag: "unique"}).
+			WithRetryAfter(time.Second),
+	)
+
+	parsed := ParseError(Response(context.Background(), err))
+
+	assert.Equal(t, "method.conflict", parsed.ID)
+	assert.Equal(t, code.AlreadyExists, parsed.Code)
+	assert.Equal(t, "Method", parsed.Title)
+	assert.Equal(t, "Already exists", parsed.Text)
+	assert.Equal(t, "users", parsed.Context)
+	assert.True(t, parsed.ShowMessage)
+	assert.Equal(t, apperr.Fields{"user_id": "42"}, parsed.Fields)
+	assert.Equal(t, []apperr.Violation{{Column: "login", Error: "is taken", Tag: "unique"}}, parsed.Violations)
+	assert.Equal(t, time.Second, parsed.RetryAfter)
+}
+
+func TestParseLegacyError(t *testing.T) {
+	st, _ := status.New(codes.NotFound, "not_found").WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: "id", Description: "not_found"},
+			{Field: "title", Description: "Title"},
+			{Field: "text", Description: "Text"},
+			{Field: "context", Description: "users"},
+			{Field: "show_message_banner", Description: "true"},
+			{Field: "errors", Description: `[{"column":"id","error":"required"}]`},
+		},
+	})
+
+	parsed := ParseError(st.Err())
+
+	assert.Equal(t, "not_found", parsed.ID)
+	assert.Equal(t, code.NotFound, parsed.Code)
+	assert.Equal(t, "Title", parsed.Title)
+	assert.Equal(t, "Text", parsed.Text)
+	assert.Equal(t, "users", parsed.Context)
+	assert.True(t, parsed.ShowMessage)
+	assert.Equal(t, []apperr.Violation{{Column: "id", Error: "required"}}, parsed.Violations)
+}
+
+func TestResponseDebug(t *testing.T) {
+	err := apperr.New("method").WithError(apperr.New("db", apperr.WithCode(code.Internal)).WithError(errors.New("connection refused")))
+
+	st := status.Convert(Response(context.Background(), err))
+	assert.Equal(t, "method.db", st.Message())
+	for _, d := range st.Details() {
+		assert.NotContains(t, fmt.Sprint(d), "connection refused")
+	}
+
+	apperr.SetLevel(level.DEVELOPMENT)
+	defer apperr.SetLevel(level.PRODUCTION)
+
+	st = status.Convert(Response(context.Background(), err))
+	assert.Equal(t, "method.db.(connection refused)", st.Message())
+
+	var debug *errdetails.DebugInfo
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.DebugInfo); ok {
+			debug = info
+		}
+	}
+	if assert.NotNil(t, debug) {
+		assert.Equal(t, "connection refused", debug.Detail)
+		assert.NotEmpty(t, debug.StackEntries)
+	}
+}
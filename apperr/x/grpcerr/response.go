@@ -4,69 +4,88 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"reflect"
 	"strconv"
-	"strings"
-	"unicode"
 
 	"github.com/c2pc/go-pkg/apperr"
 	"github.com/c2pc/go-pkg/apperr/utils/appErrors"
-	"github.com/c2pc/go-pkg/apperr/utils/translator"
+	"github.com/c2pc/go-pkg/apperr/utils/code"
+	"github.com/c2pc/go-pkg/apperr/utils/validation"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 func init() {
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
-		v.RegisterTagNameFunc(RegisterTagNameFunc)
-		translator.SetValidateTranslators(v)
+		validation.Register(v)
 	}
 }
 
 func RegisterTagNameFunc(fld reflect.StructField) string {
-	fieldName := fld.Tag.Get("key")
-	if fieldName == "-" {
-		return ""
-	}
-	return fieldName
+	return validation.RegisterTagNameFunc(fld)
 }
 
-func getNamespace(str string) string {
-	touch := strings.Index(str, ".")
-	if touch != -1 {
-		str = str[strings.Index(str, ".")+1:]
-	}
+type ValidateError = apperr.Violation
 
-	newStr := ""
-	for i, s := range str {
-		if unicode.IsUpper(s) {
-			if i != 0 && string(str[i-1]) != "." {
-				newStr += "_" + strings.ToLower(string(s))
-			} else {
-				newStr += strings.ToLower(string(s))
-			}
-			continue
-		}
-		newStr += string(s)
-	}
-	return newStr
+type ErrorItem struct {
+	ID                string        `json:"id"`
+	Code              codes.Code    `json:"code"`
+	Title             string        `json:"title"`
+	Text              string        `json:"text"`
+	Context           string        `json:"context"`
+	ShowMessageBanner bool          `json:"show_message_banner"`
+	Meta              apperr.Fields `json:"meta,omitempty"`
 }
 
-type ValidateError struct {
-	Column string `json:"column"`
-	Error  string `json:"error"`
+func errorItems(ctx context.Context, errs []apperr.Error) []ErrorItem {
+	items := make([]ErrorItem, 0, len(errs))
+	for _, err := range errs {
+		appErr := apperr.Unwrap(err)
+		title, text := apperr.Translate(appErr, GetTranslate(ctx))
+		items = append(items, ErrorItem{
+			ID:                appErr.ID,
+			Code:              code.CodeToGrpc(err.GetCode()),
+			Title:             title,
+			Text:              text,
+			Context:           appErr.Context,
+			ShowMessageBanner: appErr.ShowMessage,
+			Meta:              appErr.Fields,
+		})
+	}
+	return items
 }
 
+// Domain is the ErrorInfo domain of errors encoded by Response.
+const Domain = "apperr"
+
+// Reserved ErrorInfo metadata keys, every other key is an apperr.Fields entry.
+const (
+	metaTitle       = "apperr.title"
+	metaContext     = "apperr.context"
+	metaShowMessage = "apperr.show_message_banner"
+	metaItems       = "apperr.items"
+	metaViolations  = "apperr.violations"
+	metaDebugIDs    = "apperr.debug_ids"
+)
+
 func Response(ctx context.Context, err apperr.Error) error {
 	var syntaxError *json.SyntaxError
 	var unmarshalTypeError *json.UnmarshalTypeError
 	var invalidUnmarshalError *json.InvalidUnmarshalError
 	var validationError validator.ValidationErrors
 
-	br := &errdetails.BadRequest{}
+	defer func() {
+		method, _ := grpc.Method(ctx)
+		apperr.NotifyResponse(ctx, err, apperr.Transport{Name: apperr.TransportGRPC, Method: method})
+	}()
 
 	var childError apperr.Error
 	lastError := err.LastError()
@@ -79,50 +98,90 @@ func Response(ctx context.Context, err apperr.Error) error {
 			err = err.WithError(appErrors.ErrEmptyData.WithError(lastError))
 
 		case errors.As(lastError, &validationError):
-			err = err.WithError(appErrors.ErrValidation.WithError(childError))
-
-			appErr := apperr.Unwrap(err)
-			title, text := apperr.Translate(appErr, GetTranslate(ctx))
-
-			errs := []ValidateError{}
-			for s, v := range validationError.Translate(getTranslator(ctx)) {
-				column := getNamespace(s)
-				columnError := strings.ReplaceAll(v, column, "")
-				columnError = strings.ReplaceAll(v, "  ", " ")
-				errs = append(errs, ValidateError{Column: column, Error: columnError})
-			}
-
-			errConvert, _ := json.Marshal(errs)
-
-			st := status.New(codeToGrpc(appErr.Code), appErr.Error())
-			v := []*errdetails.BadRequest_FieldViolation{
-				{Field: "id", Description: appErr.ID},
-				{Field: "title", Description: title},
-				{Field: "text", Description: text},
-				{Field: "context", Description: appErr.Context},
-				{Field: "show_message_banner", Description: strconv.FormatBool(appErr.ShowMessage)},
-				{Field: "errors", Description: string(errConvert)},
-			}
-			br.FieldViolations = append(br.FieldViolations, v...)
-			st, _ = st.WithDetails(br)
-
-			return st.Err()
+			err = err.WithError(appErrors.ErrValidation.WithError(lastError))
+			return render(ctx, err, validation.Violations(validationError, getTranslator(ctx)))
 		}
 	}
 
+	return render(ctx, err, nil)
+}
+
+// render builds the status of err. In production its message is the error ID
+// only, the full chain with wrapped causes is sent outside production.
+func render(ctx context.Context, err apperr.Error, errs []ValidateError) error {
 	appErr := apperr.Unwrap(err)
 	title, text := apperr.Translate(appErr, GetTranslate(ctx))
 
-	st := status.New(codeToGrpc(appErr.Code), err.Error())
-	v := []*errdetails.BadRequest_FieldViolation{
-		{Field: "id", Description: appErr.ID},
-		{Field: "title", Description: title},
-		{Field: "text", Description: text},
-		{Field: "context", Description: appErr.Context},
-		{Field: "show_message_banner", Description: strconv.FormatBool(appErr.ShowMessage)},
+	if errs == nil {
+		errs = appErr.Violations
 	}
-	br.FieldViolations = append(br.FieldViolations, v...)
-	st, _ = st.WithDetails(br)
+
+	msg := appErr.ID
+	debug := err.Debug()
+	if debug != nil {
+		msg = err.Error()
+	}
+
+	st := status.New(code.CodeToGrpc(appErr.Code), msg)
+	st, _ = st.WithDetails(details(ctx, appErr, title, text, errs, debug)...)
 
 	return st.Err()
 }
+
+func details(ctx context.Context, appErr apperr.Error, title, text string, errs []ValidateError, debug *apperr.Debug) []protoadapt.MessageV1 {
+	d := []protoadapt.MessageV1{
+		errorInfo(ctx, appErr, title, errs, debug),
+		&errdetails.LocalizedMessage{Locale: GetTranslate(ctx), Message: text},
+	}
+
+	if len(errs) > 0 {
+		br := &errdetails.BadRequest{}
+		for _, e := range errs {
+			br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{Field: e.Column, Description: e.Error})
+		}
+		d = append(d, br)
+	}
+
+	if retryAfter := appErr.GetRetryAfter(); retryAfter > 0 {
+		d = append(d, &errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)})
+	}
+
+	if debug != nil {
+		d = append(d, &errdetails.DebugInfo{StackEntries: debug.Stack, Detail: debug.Error})
+	}
+
+	return d
+}
+
+func errorInfo(ctx context.Context, appErr apperr.Error, title string, errs []ValidateError, debug *apperr.Debug) *errdetails.ErrorInfo {
+	info := &errdetails.ErrorInfo{
+		Reason:   appErr.ID,
+		Domain:   Domain,
+		Metadata: make(map[string]string, len(appErr.Fields)+4),
+	}
+
+	for k, v := range appErr.Fields {
+		info.Metadata[k] = fmt.Sprint(v)
+	}
+
+	info.Metadata[metaTitle] = title
+	info.Metadata[metaContext] = appErr.Context
+	info.Metadata[metaShowMessage] = strconv.FormatBool(appErr.ShowMessage)
+
+	if len(appErr.Errors) > 0 {
+		items, _ := json.Marshal(errorItems(ctx, appErr.Errors))
+		info.Metadata[metaItems] = string(items)
+	}
+
+	if len(errs) > 0 {
+		violations, _ := json.Marshal(errs)
+		info.Metadata[metaViolations] = string(violations)
+	}
+
+	if debug != nil {
+		ids, _ := json.Marshal(debug.IDs)
+		info.Metadata[metaDebugIDs] = string(ids)
+	}
+
+	return info
+}
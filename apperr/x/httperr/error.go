@@ -0,0 +1,104 @@
+package httperr
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/c2pc/go-pkg/apperr"
+	"github.com/c2pc/go-pkg/apperr/utils/appErrors"
+	"github.com/c2pc/go-pkg/apperr/utils/code"
+)
+
+type responseBody struct {
+	ID                string          `json:"id"`
+	Title             string          `json:"title"`
+	Text              string          `json:"text"`
+	Detail            string          `json:"detail"`
+	Context           string          `json:"context"`
+	ShowMessageBanner bool            `json:"show_message_banner"`
+	Errors            []ValidateError `json:"errors"`
+	Items             []ErrorItem     `json:"items"`
+	Meta              apperr.Fields   `json:"meta"`
+}
+
+// ParseError rebuilds an apperr.Error from a response written by Response,
+// in either the default or the problem+json shape. The body is left readable.
+// A nil resp, as returned with a transport error, is read as ErrServerIsNotAvailable.
+func ParseError(resp *http.Response) apperr.Error {
+	if resp == nil {
+		return appErrors.ErrServerIsNotAvailable
+	}
+
+	httpCode := code.HttpToCode(resp.StatusCode)
+	retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+
+	if httpCode == code.Unavailable || httpCode == code.DeadlineExceeded {
+		return appErrors.ErrServerIsNotAvailable.WithRetryAfter(retryAfter)
+	}
+
+	var body responseBody
+	if resp.Body != nil {
+		data, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+		_ = json.Unmarshal(data, &body)
+	}
+
+	text := body.Text
+	if text == "" {
+		text = body.Detail
+	}
+
+	return apperr.New(body.ID,
+		apperr.WithCode(httpCode),
+		apperr.WithTitle(body.Title),
+		apperr.WithText(text),
+		apperr.WithContext(body.Context),
+		apperr.WithShowMessage(body.ShowMessageBanner),
+		apperr.WithViolations(body.Errors...),
+		apperr.WithErrors(parseItems(body.Items)...),
+		apperr.WithFields(body.Meta),
+		apperr.WithRetryAfter(retryAfter),
+	)
+}
+
+// parseRetryAfter reads both the delay-seconds and the HTTP-date form of Retry-After.
+func parseRetryAfter(value string) time.Duration {
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+		return 0
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if d := time.Until(date); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+func parseItems(items []ErrorItem) []apperr.Error {
+	errs := make([]apperr.Error, 0, len(items))
+	for _, item := range items {
+		itemCode, ok := code.Parse(item.Code)
+		if !ok {
+			itemCode = code.HttpToCode(item.Status)
+		}
+
+		errs = append(errs, apperr.New(item.ID,
+			apperr.WithCode(itemCode),
+			apperr.WithTitle(item.Title),
+			apperr.WithText(item.Text),
+			apperr.WithContext(item.Context),
+			apperr.WithShowMessage(item.ShowMessageBanner),
+			apperr.WithFields(item.Meta),
+		))
+	}
+	return errs
+}
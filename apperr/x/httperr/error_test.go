@@ -0,0 +1,217 @@
+package httperr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/c2pc/go-pkg/apperr"
+	"github.com/c2pc/go-pkg/apperr/utils/appErrors"
+	"github.com/c2pc/go-pkg/apperr/utils/code"
+	"github.com/c2pc/go-pkg/level"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+func response(accept string, err apperr.Error) *http.Response {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/users", nil)
+	c.Request.Header.Set("Accept", accept)
+	Response(c, err)
+	return w.Result()
+}
+
+func TestParseError(t *testing.T) {
+	err := apperr.New("method", apperr.WithContext("users"), apperr.WithTitle("Method")).WithError(
+		apperr.New("conflict", apperr.WithCode(code.AlreadyExists), apperr.WithText("Already exists"), apperr.WithShowMessage(true)).
+			WithField("user_id", 42).
+			WithViolations(apperr.Violation{Column: "login", Error: "is taken"}),
+	)
+
+	for _, accept := range []string{"application/json", ProblemContentType} {
+		parsed := ParseError(response(accept, err))
+
+		assert.Equal(t, "method.conflict", parsed.ID)
+		assert.Equal(t, code.AlreadyExists, parsed.Code)
+		assert.Equal(t, "Method", parsed.Title)
+		assert.Equal(t, "Already exists", parsed.Text)
+		assert.Equal(t, "users", parsed.Context)
+		assert.True(t, parsed.ShowMessage)
+		assert.Equal(t, apperr.Fields{"user_id": float64(42)}, parsed.Fields)
+		assert.Equal(t, []apperr.Violation{{Column: "login", Error: "is taken"}}, parsed.Violations)
+	}
+}
+
+func TestParseErrorUnavailable(t *testing.T) {
+	err := apperr.New("method").WithError(appErrors.ErrServerIsNotAvailable.WithRetryAfter(2 * time.Second))
+
+	parsed := ParseError(response("", err))
+
+	assert.Equal(t, appErrors.ErrServerIsNotAvailable.ID, parsed.ID)
+	assert.Equal(t, 2*time.Second, parsed.RetryAfter)
+}
+
+func TestParseErrorRetryAfterDate(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{}}
+	resp.Header.Set("Retry-After", time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+
+	parsed := ParseError(resp)
+
+	assert.Equal(t, appErrors.ErrServerIsNotAvailable.ID, parsed.ID)
+	assert.InDelta(t, time.Minute, parsed.RetryAfter, float64(2*time.Second))
+
+	resp.Header.Set("Retry-After", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+	assert.Zero(t, ParseError(resp).RetryAfter)
+
+	resp.Header.Set("Retry-After", "soon")
+	assert.Zero(t, ParseError(resp).RetryAfter)
+}
+
+func TestParseErrorNilResponse(t *testing.T) {
+	parsed := ParseError(nil)
+
+	assert.Equal(t, appErrors.ErrServerIsNotAvailable.ID, parsed.ID)
+	assert.Equal(t, code.Unavailable, parsed.Code)
+	assert.True(t, parsed.Retryable())
+}
+
+func TestResponseHook(t *testing.T) {
+	var got apperr.Error
+	var transport apperr.Transport
+	apperr.OnResponse(func(ctx context.Context, err apperr.Error, tr apperr.Transport) {
+		if tr.Name == apperr.TransportHTTP {
+			got, transport = err, tr
+		}
+	})
+
+	response("", apperr.New("method").WithError(appErrors.ErrInternal.WithError(errors.New("boom"))))
+
+	assert.Equal(t, "method", got.ID)
+	assert.EqualError(t, got.LastError(), "boom")
+	assert.Equal(t, apperr.Transport{Name: apperr.TransportHTTP, Method: http.MethodGet, Path: "/users"}, transport)
+}
+
+func TestResponseDebug(t *testing.T) {
+	err := apperr.New("method").WithError(apperr.New("db", apperr.WithCode(code.Internal)).WithError(errors.New("connection refused")))
+
+	body, _ := io.ReadAll(response("", err).Body)
+	assert.NotContains(t, string(body), "connection refused")
+	assert.NotContains(t, string(body), `"debug"`)
+
+	apperr.SetLevel(level.TEST)
+	defer apperr.SetLevel(level.PRODUCTION)
+
+	for _, accept := range []string{"application/json", ProblemContentType} {
+		var resp struct {
+			Debug apperr.Debug `json:"debug"`
+		}
+		assert.NoError(t, json.NewDecoder(response(accept, err).Body).Decode(&resp))
+		assert.Equal(t, []string{"method", "db"}, resp.Debug.IDs)
+		assert.Equal(t, "connection refused", resp.Debug.Error)
+		assert.NotEmpty(t, resp.Debug.Stack)
+	}
+}
+
+func TestWriteMatchesGin(t *testing.T) {
+	var req struct {
+		Login string `validate:"required"`
+	}
+	errs := []apperr.Error{
+		apperr.New("method").WithError(
+			apperr.New("conflict", apperr.WithCode(code.AlreadyExists), apperr.WithText("Already exists")).
+				WithField("user_id", 42).
+				WithRetryAfter(time.Second),
+		),
+		apperr.New("method").WithError(validator.New().Struct(req)),
+	}
+
+	for _, err := range errs {
+		for _, accept := range []string{"application/json", ProblemContentType} {
+			r := httptest.NewRequest(http.MethodGet, "/users?id=1", nil)
+			r.Header.Set("Accept", accept)
+			w := httptest.NewRecorder()
+			Write(w, r, err)
+
+			g := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(g)
+			c.Request = r
+			Response(c, err)
+
+			assert.Equal(t, g.Code, w.Code)
+			assert.Equal(t, g.Header(), w.Header())
+			assert.Equal(t, g.Body.String(), w.Body.String())
+			assert.True(t, c.IsAborted())
+			assert.Len(t, c.Errors, 1)
+		}
+	}
+}
+
+func TestWriteValidation(t *testing.T) {
+	var req struct {
+		Login string `validate:"required"`
+	}
+
+	w := httptest.NewRecorder()
+	Write(w, httptest.NewRequest(http.MethodPost, "/users", nil), apperr.New("method").WithError(validator.New().Struct(req)))
+
+	var resp struct {
+		ID     string          `json:"id"`
+		Errors []ValidateError `json:"errors"`
+	}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "method.validation_error", resp.ID)
+	if assert.Len(t, resp.Errors, 1) {
+		assert.Equal(t, "login", resp.Errors[0].Column)
+		assert.Equal(t, "required", resp.Errors[0].Tag)
+		assert.NotEmpty(t, resp.Errors[0].Error)
+	}
+}
+
+func TestProblem(t *testing.T) {
+	err := apperr.New("method", apperr.WithContext("users")).WithError(
+		apperr.New("not_found", apperr.WithCode(code.NotFound), apperr.WithText("Not found")),
+	)
+	decode := func(resp *http.Response) map[string]any {
+		var body map[string]any
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return body
+	}
+
+	resp := response("application/json", err)
+	assert.Equal(t, "application/json; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Equal(t, map[string]any{
+		"id":                  "method.not_found",
+		"title":               "",
+		"text":                "Not found",
+		"context":             "users",
+		"show_message_banner": false,
+	}, decode(resp))
+
+	resp = response("text/html, application/problem+json;q=0.9", err)
+	assert.Equal(t, ProblemContentType, resp.Header.Get("Content-Type"))
+	assert.Equal(t, map[string]any{
+		"type":                "/errors/method.not_found",
+		"title":               "Not Found",
+		"status":              float64(http.StatusNotFound),
+		"detail":              "Not found",
+		"instance":            "/users",
+		"id":                  "method.not_found",
+		"context":             "users",
+		"show_message_banner": false,
+	}, decode(resp))
+
+	SetConfig(Config{Format: FormatProblem, ProblemTypeURI: "https://example.com/errors/"})
+	defer SetConfig(Config{Format: FormatDefault, ProblemTypeURI: "/errors/"})
+
+	resp = response("application/json", err)
+	assert.Equal(t, ProblemContentType, resp.Header.Get("Content-Type"))
+	assert.Equal(t, "https://example.com/errors/method.not_found", decode(resp)["type"])
+}
@@ -0,0 +1,43 @@
+package httperr
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/c2pc/go-pkg/apperr"
+	"github.com/c2pc/go-pkg/apperr/utils/locale"
+	"github.com/c2pc/go-pkg/apperr/utils/validation"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Response writes err as Write does, records its translated title and text
+// in c.Errors and aborts the gin chain.
+func Response(c *gin.Context, err apperr.Error) {
+	RegisterBindingValidator()
+	err, errs := classify(c.Request, err)
+
+	title, text := apperr.Translate(apperr.Unwrap(err), GetTranslate(c))
+	_ = c.Error(errors.New(title + ": " + text)).SetType(gin.ErrorTypePrivate)
+	c.Abort()
+
+	write(c.Writer, c.Request, err, errs)
+}
+
+func GetTranslate(c *gin.Context) string {
+	return locale.FromRequest(c.Request)
+}
+
+var registerBindingOnce sync.Once
+
+// RegisterBindingValidator sets up gin's binding validator to name fields by
+// their `key` tag and to translate its messages. Response calls it, calling it
+// at startup also covers the requests bound before the first error response.
+func RegisterBindingValidator() {
+	registerBindingOnce.Do(func() {
+		if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+			validation.Register(v)
+		}
+	})
+}
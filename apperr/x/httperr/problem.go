@@ -0,0 +1,95 @@
+package httperr
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/c2pc/go-pkg/apperr"
+)
+
+const ProblemContentType = "application/problem+json"
+
+type Format int
+
+const (
+	// FormatDefault renders {id,title,text,context,show_message_banner}.
+	FormatDefault Format = iota
+	// FormatProblem renders RFC 7807 problem details.
+	FormatProblem
+)
+
+type Config struct {
+	// Format is used unless the request asks for application/problem+json in Accept.
+	Format Format
+	// ProblemTypeURI is the prefix of the problem "type" member, the error ID is appended to it.
+	ProblemTypeURI string
+}
+
+var (
+	configMu sync.RWMutex
+	config   = Config{
+		Format:         FormatDefault,
+		ProblemTypeURI: "/errors/",
+	}
+)
+
+func SetConfig(cfg Config) {
+	configMu.Lock()
+	defer configMu.Unlock()
+	config = cfg
+}
+
+func getConfig() Config {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return config
+}
+
+func isProblem(r *http.Request) bool {
+	if getConfig().Format == FormatProblem {
+		return true
+	}
+	return acceptsProblem(r.Header.Get("Accept"))
+}
+
+func acceptsProblem(accept string) bool {
+	for _, v := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(v))
+		if err == nil && mediaType == ProblemContentType {
+			return true
+		}
+	}
+	return false
+}
+
+func problem(r *http.Request, appErr apperr.Error, status int, title, text string, errs []ValidateError, debug *apperr.Debug) map[string]any {
+	if title == "" {
+		title = http.StatusText(status)
+	}
+
+	h := map[string]any{
+		"type":                getConfig().ProblemTypeURI + appErr.ID,
+		"title":               title,
+		"status":              status,
+		"detail":              text,
+		"instance":            r.URL.RequestURI(),
+		"id":                  appErr.ID,
+		"context":             appErr.Context,
+		"show_message_banner": appErr.ShowMessage,
+	}
+	if errs != nil {
+		h["errors"] = errs
+	}
+	if len(appErr.Errors) > 0 {
+		h["items"] = errorItems(r, appErr.Errors)
+	}
+	if len(appErr.Fields) > 0 {
+		h["meta"] = appErr.Fields
+	}
+	if debug != nil {
+		h["debug"] = debug
+	}
+	return h
+}
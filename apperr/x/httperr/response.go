@@ -4,60 +4,65 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
+	"math"
+	"net/http"
 	"reflect"
-	"strings"
-	"unicode"
+	"strconv"
 
 	"github.com/c2pc/go-pkg/apperr"
 	"github.com/c2pc/go-pkg/apperr/utils/appErrors"
-	"github.com/c2pc/go-pkg/apperr/utils/translator"
-	"github.com/gin-gonic/gin"
-	"github.com/gin-gonic/gin/binding"
+	"github.com/c2pc/go-pkg/apperr/utils/code"
+	"github.com/c2pc/go-pkg/apperr/utils/locale"
+	"github.com/c2pc/go-pkg/apperr/utils/validation"
 	"github.com/go-playground/validator/v10"
 )
 
-func init() {
-	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
-		v.RegisterTagNameFunc(RegisterTagNameFunc)
-		translator.SetValidateTranslators(v)
-	}
-}
-
 func RegisterTagNameFunc(fld reflect.StructField) string {
-	fieldName := fld.Tag.Get("key")
-	if fieldName == "-" {
-		return ""
-	}
-	return fieldName
+	return validation.RegisterTagNameFunc(fld)
 }
 
-func getNamespace(str string) string {
-	touch := strings.Index(str, ".")
-	if touch != -1 {
-		str = str[strings.Index(str, ".")+1:]
-	}
+type ValidateError = apperr.Violation
+
+type ErrorItem struct {
+	ID                string        `json:"id"`
+	Code              string        `json:"code"`
+	Status            int           `json:"status"`
+	Title             string        `json:"title"`
+	Text              string        `json:"text"`
+	Context           string        `json:"context"`
+	ShowMessageBanner bool          `json:"show_message_banner"`
+	Meta              apperr.Fields `json:"meta,omitempty"`
+}
 
-	newStr := ""
-	for i, s := range str {
-		if unicode.IsUpper(s) {
-			if i != 0 && string(str[i-1]) != "." {
-				newStr += "_" + strings.ToLower(string(s))
-			} else {
-				newStr += strings.ToLower(string(s))
-			}
-			continue
-		}
-		newStr += string(s)
+func errorItems(r *http.Request, errs []apperr.Error) []ErrorItem {
+	items := make([]ErrorItem, 0, len(errs))
+	for _, err := range errs {
+		appErr := apperr.Unwrap(err)
+		title, text := apperr.Translate(appErr, locale.FromRequest(r))
+		items = append(items, ErrorItem{
+			ID:                appErr.ID,
+			Code:              err.GetCode().String(),
+			Status:            code.CodeToHttp(err.GetCode()),
+			Title:             title,
+			Text:              text,
+			Context:           appErr.Context,
+			ShowMessageBanner: appErr.ShowMessage,
+			Meta:              appErr.Fields,
+		})
 	}
-	return newStr
+	return items
 }
 
-type ValidateError struct {
-	Column string `json:"column"`
-	Error  string `json:"error"`
+// Write writes err to w as Response does, negotiating the language from r.
+// It is meant for plain net/http handlers.
+func Write(w http.ResponseWriter, r *http.Request, err apperr.Error) {
+	err, errs := classify(r, err)
+	write(w, r, err, errs)
 }
 
-func Response(c *gin.Context, err apperr.Error) {
+// classify wraps a decoding or validation error that is not an apperr.Error
+// into its apperr counterpart and returns the violations of a validation error.
+func classify(r *http.Request, err apperr.Error) (apperr.Error, []ValidateError) {
 	var syntaxError *json.SyntaxError
 	var unmarshalTypeError *json.UnmarshalTypeError
 	var invalidUnmarshalError *json.InvalidUnmarshalError
@@ -65,54 +70,87 @@ func Response(c *gin.Context, err apperr.Error) {
 
 	var childError apperr.Error
 	lastError := err.LastError()
-	if !errors.As(lastError, &childError) {
-		switch {
-		case errors.As(lastError, &syntaxError), errors.As(lastError, &unmarshalTypeError), errors.As(lastError, &invalidUnmarshalError):
-			err = err.WithError(appErrors.ErrSyntax.WithError(lastError))
-
-		case errors.Is(lastError, io.EOF), errors.Is(lastError, io.ErrUnexpectedEOF), errors.Is(lastError, io.ErrNoProgress):
-			err = err.WithError(appErrors.ErrEmptyData.WithError(lastError))
-
-		case errors.As(lastError, &validationError):
-			err = err.WithError(appErrors.ErrValidation.WithError(lastError))
-
-			appErr := apperr.Unwrap(err)
-			title, text := apperr.Translate(appErr, GetTranslate(c))
-
-			errs := []ValidateError{}
-			for s, v := range validationError.Translate(getTranslator(c)) {
-				column := getNamespace(s)
-				columnError := strings.ReplaceAll(v, column, "")
-				columnError = strings.ReplaceAll(v, "  ", " ")
-				errs = append(errs, ValidateError{Column: column, Error: columnError})
-			}
-
-			_ = c.Error(errors.New(title + ": " + text)).SetType(gin.ErrorTypePrivate)
-
-			c.AbortWithStatusJSON(codeToHttp(appErr.Code), gin.H{
-				"id":                  appErr.ID,
-				"title":               title,
-				"text":                text,
-				"context":             appErr.Context,
-				"show_message_banner": appErr.ShowMessage,
-				"errors":              errs,
-			})
-
-			return
-		}
+	if errors.As(lastError, &childError) {
+		return err, nil
 	}
 
+	switch {
+	case errors.As(lastError, &syntaxError), errors.As(lastError, &unmarshalTypeError), errors.As(lastError, &invalidUnmarshalError):
+		return err.WithError(appErrors.ErrSyntax.WithError(lastError)), nil
+
+	case errors.Is(lastError, io.EOF), errors.Is(lastError, io.ErrUnexpectedEOF), errors.Is(lastError, io.ErrNoProgress):
+		return err.WithError(appErrors.ErrEmptyData.WithError(lastError)), nil
+
+	case errors.As(lastError, &validationError):
+		return err.WithError(appErrors.ErrValidation.WithError(lastError)), validation.Violations(validationError, getTranslator(r))
+	}
+
+	return err, nil
+}
+
+func write(w http.ResponseWriter, r *http.Request, err apperr.Error, errs []ValidateError) {
+	render(w, r, err, errs)
+	apperr.NotifyResponse(r.Context(), err, apperr.Transport{
+		Name:   apperr.TransportHTTP,
+		Method: r.Method,
+		Path:   r.URL.Path,
+	})
+}
+
+func render(w http.ResponseWriter, r *http.Request, err apperr.Error, errs []ValidateError) {
 	appErr := apperr.Unwrap(err)
-	title, text := apperr.Translate(appErr, GetTranslate(c))
+	title, text := apperr.Translate(appErr, locale.FromRequest(r))
+
+	if errs == nil {
+		errs = appErr.Violations
+	}
 
-	_ = c.Error(errors.New(title + ": " + text)).SetType(gin.ErrorTypePrivate)
-	
-	c.AbortWithStatusJSON(codeToHttp(appErr.Code), gin.H{
+	status := code.CodeToHttp(appErr.Code)
+
+	if d := appErr.GetRetryAfter(); d > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(d.Seconds()))))
+	}
+
+	if isProblem(r) {
+		w.Header().Set("Content-Type", ProblemContentType)
+		writeJSON(w, status, problem(r, appErr, status, title, text, errs, err.Debug()))
+		return
+	}
+
+	body := map[string]any{
 		"id":                  appErr.ID,
 		"title":               title,
 		"text":                text,
 		"context":             appErr.Context,
 		"show_message_banner": appErr.ShowMessage,
-	})
+	}
+	if errs != nil {
+		body["errors"] = errs
+	}
+	if len(appErr.Errors) > 0 {
+		body["items"] = errorItems(r, appErr.Errors)
+	}
+	if len(appErr.Fields) > 0 {
+		body["meta"] = appErr.Fields
+	}
+	if debug := err.Debug(); debug != nil {
+		body["debug"] = debug
+	}
+
+	writeJSON(w, status, body)
+}
+
+// writeJSON writes body the way gin's JSON render does.
+func writeJSON(w http.ResponseWriter, status int, body map[string]any) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
 
+	if len(w.Header().Values("Content-Type")) == 0 {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
 }
@@ -43,34 +43,75 @@ var CipherSuites = []uint16{
 	tls.TLS_CHACHA20_POLY1305_SHA256,
 }
 
-const (
-	NoAvailableServersNotify = "no available servers"
-	ChangedUrlNotify         = "url changed"
-)
-
 var (
-	ErrNoAvailableServers = errors.New(NoAvailableServersNotify)
+	ErrNoAvailableServers = errors.New("no available servers")
+	ErrClientClosed       = errors.New("balancer client closed")
 )
 
+type Config struct {
+	// Strategy chooses the backend of every request, Failover by default.
+	Strategy Strategy
+	// HealthCheck configures the background probes of the backends.
+	HealthCheck HealthCheck
+	// Discovery, if set, replaces the URL list every DiscoveryInterval and, for
+	// a Watcher such as File, after every change. It also provides the initial
+	// list when NewClient gets none.
+	Discovery Source
+	// DiscoveryInterval defaults to 30s.
+	DiscoveryInterval time.Duration
+}
+
 type Client struct {
 	mu         sync.RWMutex
-	urls       []string
+	backends   []*Backend
 	currentIdx int
-	listeners  []chan string
+	strategy   Strategy
+	health     HealthCheck
 	httpClient *http.Client
+
+	subscriptions map[*Subscription]struct{}
+
+	ctx       context.Context
+	cancel    context.CancelFunc
+	stopClose func() bool
+	lifeMu    sync.Mutex
+	closed    bool
+	wg        sync.WaitGroup
 }
 
 func NewClient(urls []string) (*Client, error) {
+	return NewClientContext(context.Background(), urls, Config{})
+}
+
+func NewClientWithConfig(urls []string, cfg Config) (*Client, error) {
+	return NewClientContext(context.Background(), urls, cfg)
+}
+
+// NewClientContext creates a client that is closed when ctx is done.
+func NewClientContext(ctx context.Context, urls []string, cfg Config) (*Client, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(urls) == 0 && cfg.Discovery != nil {
+		discovered, err := cfg.Discovery.URLs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		urls = discovered
+	}
 	if len(urls) == 0 {
 		return nil, errors.New("no urls provided")
 	}
 	for i, u := range urls {
-		if u == "" {
-			return nil, errors.New("empty balancer url")
-		}
-		if !strings.HasSuffix(u, "/") {
-			urls[i] += "/"
+		normalized, err := normalizeURL(u)
+		if err != nil {
+			return nil, err
 		}
+		urls[i] = normalized
+	}
+
+	if cfg.Strategy == nil {
+		cfg.Strategy = Failover()
 	}
 
 	customTransport := http.DefaultTransport.(*http.Transport).Clone()
@@ -80,134 +121,258 @@ func NewClient(urls []string) (*Client, error) {
 	}
 
 	c := &Client{
-		urls:       urls,
-		currentIdx: -1,
-		listeners:  []chan string{},
+		currentIdx:    -1,
+		strategy:      cfg.Strategy,
+		health:        cfg.HealthCheck.withDefaults(),
+		subscriptions: map[*Subscription]struct{}{},
 		httpClient: &http.Client{
 			Transport: customTransport,
 			Timeout:   10 * time.Second,
 		},
 	}
+	c.ctx, c.cancel = context.WithCancel(ctx)
+	for _, u := range urls {
+		c.backends = append(c.backends, c.newBackend(u))
+	}
 
 	if err := c.findWorkingServer(); err != nil {
+		c.Close()
 		return nil, err
 	}
 
-	go c.monitorServers()
+	for _, b := range c.backends {
+		c.spawn(func() { c.monitorBackend(b) })
+	}
+	if cfg.Discovery != nil {
+		interval := cfg.DiscoveryInterval
+		if interval <= 0 {
+			interval = 30 * time.Second
+		}
+		var changes <-chan struct{}
+		if w, ok := cfg.Discovery.(Watcher); ok {
+			if ch, err := w.Watch(c.ctx); err == nil {
+				changes = ch
+			}
+		}
+		c.spawn(func() { c.discover(cfg.Discovery, interval, changes) })
+	}
+	c.lifeMu.Lock()
+	c.stopClose = context.AfterFunc(ctx, func() { c.Close() })
+	c.lifeMu.Unlock()
 
 	return c, nil
 }
 
-func (c *Client) findWorkingServer() error {
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+// Close stops the health checks and waits for them, closes the subscriptions
+// and makes later requests fail with ErrClientClosed. It is safe to call more than once.
+func (c *Client) Close() {
+	c.lifeMu.Lock()
+	if c.closed {
+		c.lifeMu.Unlock()
+		return
+	}
+	c.closed = true
+	stopClose := c.stopClose
+	c.lifeMu.Unlock()
 
+	if stopClose != nil {
+		stopClose()
+	}
+	c.cancel()
+	c.wg.Wait()
+
+	c.mu.Lock()
+	subscriptions := c.subscriptions
+	c.subscriptions = map[*Subscription]struct{}{}
+	c.mu.Unlock()
+	for s := range subscriptions {
+		s.close()
+	}
+
+	c.httpClient.CloseIdleConnections()
+}
+
+func (c *Client) isClosed() bool {
+	c.lifeMu.Lock()
+	defer c.lifeMu.Unlock()
+	return c.closed
+}
+
+// spawn runs f in a goroutine Close waits for, it reports false once the client is closed.
+func (c *Client) spawn(f func()) bool {
+	c.lifeMu.Lock()
+	defer c.lifeMu.Unlock()
+	if c.closed {
+		return false
+	}
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		f()
+	}()
+	return true
+}
+
+// findWorkingServer checks every backend and returns once one of them is
+// healthy. The active backend is kept up to date by checkBackend.
+func (c *Client) findWorkingServer() error {
 	var wg sync.WaitGroup
-	resultCh := make(chan int, 1)
+	resultCh := make(chan struct{}, 1)
 	var once sync.Once
 
 	c.mu.RLock()
-	urls := c.urls
+	backends := c.backends
 	c.mu.RUnlock()
 
-	for i := range urls {
+	for _, b := range backends {
 		wg.Add(1)
-		go func(idx int) {
+		if !c.spawn(func() {
 			defer wg.Done()
-			if c.checkServerAvailability(ctx, idx) {
+			if c.checkBackend(b.ctx, b) {
 				once.Do(func() {
-					resultCh <- idx
-					cancel()
+					resultCh <- struct{}{}
 				})
 			}
-		}(i)
+		}) {
+			wg.Done()
+		}
 	}
 
-	go func() {
+	if !c.spawn(func() {
 		wg.Wait()
 		close(resultCh)
-	}()
+	}) {
+		wg.Wait()
+		close(resultCh)
+	}
 
-	idx, ok := <-resultCh
-	if !ok {
+	if _, ok := <-resultCh; !ok {
 		return ErrNoAvailableServers
 	}
-
-	c.mu.Lock()
-	c.currentIdx = idx
-	c.mu.Unlock()
 	return nil
 }
 
-func (c *Client) checkServerAvailability(ctx context.Context, index int) bool {
-	c.mu.RLock()
-	if index < 0 || index >= len(c.urls) {
-		c.mu.RUnlock()
+// checkBackend probes b and records the result, it reports whether b is healthy.
+func (c *Client) checkBackend(ctx context.Context, b *Backend) bool {
+	passed := c.health.probe(ctx, c.httpClient, b.url)
+	if ctx.Err() != nil {
 		return false
 	}
-	urlToCheck := c.urls[index]
-	c.mu.RUnlock()
-
-	reqCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
-	defer cancel()
-
-	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, urlToCheck, nil)
-	if err != nil {
-		return false
+	if b.report(passed, c.health.Rise, c.health.Fall) {
+		if b.Healthy() {
+			c.publish(Event{Type: EventBackendUp, URL: b.url})
+		} else {
+			c.publish(Event{Type: EventBackendDown, URL: b.url})
+		}
+		c.updateActive()
 	}
+	return b.Healthy()
+}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return false
+// markDown takes b down and moves away from it if it is active. It probes b
+// again right away, so a transient failure does not wait for the next interval.
+func (c *Client) markDown(b *Backend) {
+	if b.markDown() {
+		c.publish(Event{Type: EventBackendDown, URL: b.url})
+		c.spawn(func() { c.checkBackend(b.ctx, b) })
 	}
-	defer resp.Body.Close()
+	c.updateActive()
+}
 
-	return true
+// reportFailure counts a failed request towards the Fall threshold of b.
+func (c *Client) reportFailure(b *Backend) {
+	if !b.report(false, c.health.Rise, c.health.Fall) {
+		return
+	}
+	c.publish(Event{Type: EventBackendDown, URL: b.url})
+	c.updateActive()
+	c.spawn(func() { c.checkBackend(b.ctx, b) })
 }
 
-func (c *Client) monitorServers() {
-	ticker := time.NewTicker(10 * time.Second)
+func (c *Client) monitorBackend(b *Backend) {
+	ticker := time.NewTicker(c.health.Interval)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		c.mu.RLock()
-		idx := c.currentIdx
-		c.mu.RUnlock()
+	for {
+		select {
+		case <-b.ctx.Done():
+			return
+		case <-ticker.C:
+			c.checkBackend(b.ctx, b)
+		}
+	}
+}
+
+// updateActive moves away from an active backend that went down, or picks one
+// when none was available.
+func (c *Client) updateActive() {
+	c.mu.Lock()
+	idx := c.currentIdx
+	if idx >= 0 && idx < len(c.backends) && c.backends[idx].Healthy() {
+		c.mu.Unlock()
+		return
+	}
 
-		if idx < 0 || idx >= len(c.urls) {
-			if err := c.findWorkingServer(); err != nil {
-				c.notifyFailure()
-			} else {
-				c.notifyUrlChanged()
-			}
-			continue
+	from := ""
+	if idx >= 0 && idx < len(c.backends) {
+		from = c.backends[idx].url
+	}
+	switched := c.switchToHealthy(idx)
+	to := ""
+	if switched {
+		to = c.backends[c.currentIdx].url
+	}
+	c.mu.Unlock()
+
+	switch {
+	case switched:
+		c.publish(Event{Type: EventActiveChanged, From: from, To: to})
+		if from == "" {
+			c.publish(Event{Type: EventRecovered, To: to})
 		}
+	case from != "":
+		c.publish(Event{Type: EventAllDown, From: from})
+	}
+}
 
-		if !c.checkServerAvailability(context.Background(), idx) {
-			oldIdx := idx
-			if !c.switchToNextServer() {
-				c.notifyFailure()
-				continue
-			}
-			if c.currentIdx != oldIdx {
-				c.notifyUrlChanged()
-			}
+// switchToHealthy makes the next healthy backend after idx active, c.mu must be held.
+func (c *Client) switchToHealthy(idx int) bool {
+	for i := 1; i <= len(c.backends); i++ {
+		next := (idx + i) % len(c.backends)
+		if c.backends[next].Healthy() {
+			c.currentIdx = next
+			return true
 		}
 	}
+
+	c.currentIdx = -1
+	return false
 }
 
+// switchToNextServer takes the active backend down and makes the next healthy
+// one active. Without an active backend it probes all of them.
 func (c *Client) switchToNextServer() bool {
-	err := c.findWorkingServer()
-	if err != nil {
-		c.mu.Lock()
-		c.currentIdx = -1
-		c.mu.Unlock()
-		return false
+	c.mu.RLock()
+	var active *Backend
+	if c.currentIdx >= 0 && c.currentIdx < len(c.backends) {
+		active = c.backends[c.currentIdx]
 	}
-	return true
+	c.mu.RUnlock()
+
+	if active == nil {
+		return c.findWorkingServer() == nil
+	}
+
+	c.markDown(active)
+	return c.GetCurrentURL() != ""
 }
 
 func (c *Client) SendRequest(originalReq *http.Request, skip bool) (*http.Response, error) {
+	if c.isClosed() {
+		return nil, ErrClientClosed
+	}
+
 	var bodyBytes []byte
 	if originalReq.Body != nil {
 		b, err := io.ReadAll(originalReq.Body)
@@ -217,30 +382,91 @@ func (c *Client) SendRequest(originalReq *http.Request, skip bool) (*http.Respon
 		_ = originalReq.Body.Close()
 		bodyBytes = b
 	}
-	return c.doRequestWithRetry(originalReq, bodyBytes, skip)
+	return c.doRequestWithRetry(originalReq, bodyBytes, skip, nil)
 }
 
-func (c *Client) doRequestWithRetry(originalReq *http.Request, bodyBytes []byte, skip bool) (*http.Response, error) {
+// next returns the backend chosen by the strategy among the healthy ones
+// except failed, nil if there are none.
+func (c *Client) next(failed *Backend) *Backend {
 	c.mu.RLock()
-	idx := c.currentIdx
-	c.mu.RUnlock()
+	defer c.mu.RUnlock()
+
+	if c.currentIdx < 0 || c.currentIdx >= len(c.backends) {
+		return nil
+	}
+
+	healthy := make([]*Backend, 0, len(c.backends))
+	for i := range c.backends {
+		b := c.backends[(c.currentIdx+i)%len(c.backends)]
+		if b.Healthy() && b != failed {
+			healthy = append(healthy, b)
+		}
+	}
+	if len(healthy) == 0 {
+		return nil
+	}
+
+	return c.strategy.Next(healthy)
+}
+
+// doRequestWithRetry sends the request to the backend chosen by the strategy.
+// Unless skip is set, a failed request is retried once on another backend, or
+// on the same one if it is the only one and passes a probe.
+func (c *Client) doRequestWithRetry(originalReq *http.Request, bodyBytes []byte, skip bool, failed *Backend) (*http.Response, error) {
+	if c.isClosed() {
+		return nil, ErrClientClosed
+	}
 
-	if idx < 0 || idx >= len(c.urls) {
+	b := c.next(failed)
+	if b == nil {
 		return nil, ErrNoAvailableServers
 	}
 
-	reqCopy := cloneRequest(originalReq, c.urls[idx], bodyBytes)
+	reqCopy := cloneRequest(originalReq, b.url, bodyBytes)
 
+	b.outstanding.Add(1)
+	start := time.Now()
 	resp, err := c.httpClient.Do(reqCopy)
 	if err != nil {
-		if !skip && c.switchToNextServer() {
-			return c.doRequestWithRetry(originalReq, bodyBytes, true)
+		b.outstanding.Add(-1)
+		if originalReq.Context().Err() != nil {
+			return nil, err
+		}
+		b.fail(c.httpClient.Timeout)
+		c.reportFailure(b)
+		if !skip {
+			failed := b
+			if c.next(b) == nil {
+				// b is the only candidate, retry on it if it passes a probe.
+				c.checkBackend(b.ctx, b)
+				failed = nil
+			}
+			resp, retryErr := c.doRequestWithRetry(originalReq, bodyBytes, true, failed)
+			if !errors.Is(retryErr, ErrNoAvailableServers) {
+				return resp, retryErr
+			}
 		}
 		return nil, err
 	}
+	b.observe(time.Since(start))
+	resp.Body = &trackedBody{ReadCloser: resp.Body, done: func() { b.outstanding.Add(-1) }}
+
 	return resp, nil
 }
 
+// trackedBody calls done once when the body is closed.
+type trackedBody struct {
+	io.ReadCloser
+	once sync.Once
+	done func()
+}
+
+func (t *trackedBody) Close() error {
+	err := t.ReadCloser.Close()
+	t.once.Do(t.done)
+	return err
+}
+
 func cloneRequest(originalReq *http.Request, baseURL string, bodyBytes []byte) *http.Request {
 	srvURL, _ := url.Parse(strings.TrimRight(baseURL, "/"))
 
@@ -267,28 +493,15 @@ func cloneRequest(originalReq *http.Request, baseURL string, bodyBytes []byte) *
 func (c *Client) GetCurrentURL() string {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	if c.currentIdx >= 0 && c.currentIdx < len(c.urls) {
-		return c.urls[c.currentIdx]
+	if c.currentIdx >= 0 && c.currentIdx < len(c.backends) {
+		return c.backends[c.currentIdx].url
 	}
 	return ""
 }
 
+// SwitchToNextServer takes the active backend down and makes the next healthy
+// backend active. The old backend is probed right away and rejoins once it
+// passes Rise health checks. It reports whether a backend is active.
 func (c *Client) SwitchToNextServer() bool {
 	return c.switchToNextServer()
 }
-
-func (c *Client) RegisterListener(ch chan string) {
-	c.listeners = append(c.listeners, ch)
-}
-
-func (c *Client) notifyUrlChanged() {
-	for _, ch := range c.listeners {
-		ch <- ChangedUrlNotify
-	}
-}
-
-func (c *Client) notifyFailure() {
-	for _, ch := range c.listeners {
-		ch <- NoAvailableServersNotify
-	}
-}
@@ -0,0 +1,538 @@
+package balancer
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+// checkAll probes every backend and waits for the results.
+func (c *Client) checkAll() {
+	c.mu.RLock()
+	backends := c.backends
+	c.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, b := range backends {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.checkBackend(b.ctx, b)
+		}()
+	}
+	wg.Wait()
+}
+
+func backends(urls ...string) []*Backend {
+	bs := make([]*Backend, 0, len(urls))
+	for _, u := range urls {
+		b := newBackend(u)
+		b.healthy.Store(true)
+		bs = append(bs, b)
+	}
+	return bs
+}
+
+func pick(s Strategy, bs []*Backend, n int) map[string]int {
+	counts := map[string]int{}
+	for i := 0; i < n; i++ {
+		counts[s.Next(bs).URL()]++
+	}
+	return counts
+}
+
+func TestStrategies(t *testing.T) {
+	bs := backends("a/", "b/", "c/")
+
+	assert.Equal(t, map[string]int{"a/": 6}, pick(Failover(), bs, 6))
+	assert.Equal(t, map[string]int{"a/": 2, "b/": 2, "c/": 2}, pick(RoundRobin(), bs, 6))
+	assert.Equal(t, map[string]int{"a/": 3, "b/": 2, "c/": 1}, pick(WeightedRoundRobin(map[string]int{"a": 3, "b/": 2}), bs, 6))
+
+	bs[0].outstanding.Store(2)
+	bs[1].outstanding.Store(1)
+	bs[2].outstanding.Store(3)
+	assert.Equal(t, "b/", LeastOutstanding().Next(bs).URL())
+	assert.NotEqual(t, "c/", RandomTwoChoices().Next(bs).URL())
+
+	bs[0].observe(10 * time.Millisecond)
+	bs[1].observe(40 * time.Millisecond)
+	bs[2].observe(5 * time.Millisecond)
+	assert.Equal(t, "c/", EWMA().Next(bs).URL())
+
+	bs[0].observe(110 * time.Millisecond)
+	assert.Equal(t, 40*time.Millisecond, bs[0].Latency())
+
+	wrr := WeightedRoundRobin(nil).(*weightedRoundRobin)
+	wrr.Next(bs)
+	wrr.Next(bs[:1])
+	assert.Len(t, wrr.current, 1)
+
+	bs[2].fail(time.Second)
+	assert.Equal(t, "b/", EWMA().Next(bs).URL())
+}
+
+func TestClientStrategy(t *testing.T) {
+	hits := map[string]int{}
+	handler := func(name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/api" {
+				hits[name]++
+			}
+		}
+	}
+	srv1 := httptest.NewServer(handler("srv1"))
+	defer srv1.Close()
+	srv2 := httptest.NewServer(handler("srv2"))
+	defer srv2.Close()
+
+	c, err := NewClientWithConfig([]string{srv1.URL, srv2.URL}, Config{Strategy: RoundRobin()})
+	assert.NoError(t, err)
+	defer c.Close()
+
+	c.checkAll()
+	for i := 0; i < 4; i++ {
+		req, _ := http.NewRequest(http.MethodGet, "/api", nil)
+		resp, err := c.SendRequest(req, false)
+		assert.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+
+	assert.Equal(t, map[string]int{"srv1": 2, "srv2": 2}, hits)
+}
+
+func TestClientFailingBackend(t *testing.T) {
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			return
+		}
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		_ = conn.Close()
+	}))
+	defer broken.Close()
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer healthy.Close()
+
+	strategies := map[string]Strategy{
+		"failover":           Failover(),
+		"round robin":        RoundRobin(),
+		"weighted":           WeightedRoundRobin(nil),
+		"least outstanding":  LeastOutstanding(),
+		"ewma":               EWMA(),
+		"random two choices": RandomTwoChoices(),
+	}
+	for name, strategy := range strategies {
+		t.Run(name, func(t *testing.T) {
+			c, err := NewClientWithConfig([]string{broken.URL, healthy.URL}, Config{
+				Strategy:    strategy,
+				HealthCheck: HealthCheck{Path: "/health"},
+			})
+			assert.NoError(t, err)
+			defer c.Close()
+
+			for i := 0; i < 10; i++ {
+				req, _ := http.NewRequest(http.MethodGet, "/api", nil)
+				resp, err := c.SendRequest(req, false)
+				if assert.NoError(t, err) {
+					_ = resp.Body.Close()
+				}
+			}
+		})
+	}
+}
+
+func TestOutstandingUntilBodyClosed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	c, err := NewClient([]string{srv.URL})
+	assert.NoError(t, err)
+	defer c.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	resp, err := c.SendRequest(req, false)
+	assert.NoError(t, err)
+
+	b := c.next(nil)
+	assert.Equal(t, int64(1), b.Outstanding())
+	_ = resp.Body.Close()
+	_ = resp.Body.Close()
+	assert.Equal(t, int64(0), b.Outstanding())
+}
+
+func TestHealthCheck(t *testing.T) {
+	status := http.StatusOK
+	var mu sync.Mutex
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.URL.Path != "/health" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer srv.Close()
+
+	hc := HealthCheck{
+		Path:      "/health",
+		MatchBody: func(body []byte) bool { return bytes.Contains(body, []byte(`"ok"`)) },
+		Rise:      2,
+		Fall:      2,
+	}.withDefaults()
+
+	assert.True(t, hc.probe(context.Background(), srv.Client(), srv.URL+"/"))
+	assert.False(t, HealthCheck{Path: "/missing"}.withDefaults().probe(context.Background(), srv.Client(), srv.URL+"/"))
+
+	mu.Lock()
+	status = http.StatusInternalServerError
+	mu.Unlock()
+	assert.False(t, hc.probe(context.Background(), srv.Client(), srv.URL+"/"))
+
+	b := newBackend(srv.URL + "/")
+	assert.True(t, b.report(true, hc.Rise, hc.Fall))
+	assert.False(t, b.report(false, hc.Rise, hc.Fall))
+	assert.True(t, b.Healthy())
+	assert.True(t, b.report(false, hc.Rise, hc.Fall))
+	assert.False(t, b.Healthy())
+	assert.False(t, b.report(true, hc.Rise, hc.Fall))
+	assert.True(t, b.report(true, hc.Rise, hc.Fall))
+	assert.True(t, b.Healthy())
+}
+
+func TestClientFailover(t *testing.T) {
+	srv1 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv1.Close()
+	srv2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv2.Close()
+
+	c, err := NewClient([]string{srv1.URL, srv2.URL})
+	assert.NoError(t, err)
+	defer c.Close()
+	assert.Equal(t, srv2.URL+"/", c.GetCurrentURL())
+}
+
+func TestSwitchToNextServer(t *testing.T) {
+	newServer := func() *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	}
+	srv1, srv2 := newServer(), newServer()
+	defer srv1.Close()
+	defer srv2.Close()
+
+	c, err := NewClientWithConfig([]string{srv1.URL, srv2.URL}, Config{HealthCheck: HealthCheck{Fall: 3}})
+	assert.NoError(t, err)
+	defer c.Close()
+	c.checkAll()
+
+	active := c.GetCurrentURL()
+	assert.True(t, c.SwitchToNextServer())
+	next := c.GetCurrentURL()
+	assert.NotEqual(t, active, next)
+	assert.NotEmpty(t, next)
+
+	assert.Eventually(t, func() bool {
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+		return c.backends[0].Healthy() && c.backends[1].Healthy()
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, next, c.GetCurrentURL())
+}
+
+func TestTransientFailure(t *testing.T) {
+	var mu sync.Mutex
+	drop := 1
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == http.MethodPost && drop > 0 {
+			drop--
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			_ = conn.Close()
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewClient([]string{srv.URL})
+	assert.NoError(t, err)
+	defer c.Close()
+
+	send := func(skip bool) error {
+		req, _ := http.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte("{}")))
+		resp, err := c.SendRequest(req, skip)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		return err
+	}
+
+	assert.NoError(t, send(false))
+	assert.NoError(t, send(false))
+
+	mu.Lock()
+	drop = 1
+	mu.Unlock()
+	assert.Error(t, send(true))
+	assert.Eventually(t, func() bool { return c.GetCurrentURL() != "" }, time.Second, time.Millisecond)
+	assert.NoError(t, send(true))
+}
+
+func TestClose(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	c, err := NewClientWithConfig([]string{srv.URL}, Config{HealthCheck: HealthCheck{Interval: time.Millisecond}})
+	assert.NoError(t, err)
+
+	sub := c.Subscribe(1, DropNewest)
+
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	resp, err := c.SendRequest(req, false)
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+
+	c.Close()
+	c.Close()
+
+	_, ok := <-sub.Events()
+	assert.False(t, ok)
+
+	_, err = c.SendRequest(req, false)
+	assert.ErrorIs(t, err, ErrClientClosed)
+}
+
+func TestNewClientContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c, err := NewClientContext(ctx, []string{srv.URL}, Config{})
+	assert.NoError(t, err)
+
+	cancel()
+	assert.Eventually(t, c.isClosed, time.Second, time.Millisecond)
+
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	_, err = c.SendRequest(req, false)
+	assert.ErrorIs(t, err, ErrClientClosed)
+
+	_, err = NewClientContext(ctx, []string{srv.URL}, Config{})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestEvents(t *testing.T) {
+	var mu sync.Mutex
+	up := map[string]bool{"srv1": true, "srv2": true}
+	handler := func(name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			if !up[name] {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}
+	}
+	srv1 := httptest.NewServer(handler("srv1"))
+	defer srv1.Close()
+	srv2 := httptest.NewServer(handler("srv2"))
+	defer srv2.Close()
+	set := func(name string, v bool) {
+		mu.Lock()
+		up[name] = v
+		mu.Unlock()
+	}
+
+	c, err := NewClient([]string{srv1.URL, srv2.URL})
+	assert.NoError(t, err)
+	defer c.Close()
+	c.checkAll()
+
+	sub := c.Subscribe(0, DropNewest)
+	defer c.Unsubscribe(sub)
+	next := func() Event {
+		select {
+		case ev := <-sub.Events():
+			ev.Time = time.Time{}
+			return ev
+		case <-time.After(time.Second):
+			return Event{}
+		}
+	}
+
+	active := c.GetCurrentURL()
+	other := srv1.URL + "/"
+	name, otherName := "srv2", "srv1"
+	if active == other {
+		other = srv2.URL + "/"
+		name, otherName = "srv1", "srv2"
+	}
+
+	set(name, false)
+	c.checkAll()
+	assert.Equal(t, Event{Type: EventBackendDown, URL: active}, next())
+	assert.Equal(t, Event{Type: EventActiveChanged, From: active, To: other}, next())
+
+	set(otherName, false)
+	c.checkAll()
+	assert.Equal(t, Event{Type: EventBackendDown, URL: other}, next())
+	assert.Equal(t, Event{Type: EventAllDown, From: other}, next())
+
+	set(name, true)
+	c.checkAll()
+	assert.Equal(t, Event{Type: EventBackendUp, URL: active}, next())
+	assert.Equal(t, Event{Type: EventActiveChanged, To: active}, next())
+	assert.Equal(t, Event{Type: EventRecovered, To: active}, next())
+}
+
+func TestSubscriptionDropPolicy(t *testing.T) {
+	newest := &Subscription{ch: make(chan Event, 1), policy: DropNewest}
+	oldest := &Subscription{ch: make(chan Event, 1), policy: DropOldest}
+	for _, s := range []*Subscription{newest, oldest} {
+		s.deliver(Event{URL: "first"})
+		s.deliver(Event{URL: "second"})
+		assert.Equal(t, uint64(1), s.Dropped())
+	}
+
+	assert.Equal(t, "first", (<-newest.Events()).URL)
+	assert.Equal(t, "second", (<-oldest.Events()).URL)
+}
+
+func TestMembership(t *testing.T) {
+	newServer := func() *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	}
+	srv1, srv2, srv3 := newServer(), newServer(), newServer()
+	defer srv1.Close()
+	defer srv2.Close()
+	defer srv3.Close()
+
+	c, err := NewClientWithConfig([]string{srv1.URL}, Config{Strategy: RoundRobin()})
+	assert.NoError(t, err)
+	defer c.Close()
+
+	sub := c.Subscribe(0, DropNewest)
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			req, _ := http.NewRequest(http.MethodGet, "/", nil)
+			if resp, err := c.SendRequest(req, false); err == nil {
+				_ = resp.Body.Close()
+			}
+		}
+	}()
+
+	assert.NoError(t, c.AddURL(srv2.URL))
+	assert.NoError(t, c.AddURL(srv2.URL+"/"))
+	assert.Equal(t, []string{srv1.URL + "/", srv2.URL + "/"}, c.URLs())
+	assert.Equal(t, EventBackendUp, (<-sub.Events()).Type)
+
+	assert.NoError(t, c.RemoveURL(srv1.URL))
+	ev := <-sub.Events()
+	assert.Equal(t, EventActiveChanged, ev.Type)
+	assert.Equal(t, srv1.URL+"/", ev.From)
+	assert.Equal(t, srv2.URL+"/", ev.To)
+	assert.Equal(t, srv2.URL+"/", c.GetCurrentURL())
+	assert.Error(t, c.RemoveURL(srv1.URL))
+
+	assert.NoError(t, c.SetURLs([]string{srv3.URL, srv2.URL}))
+	assert.Equal(t, []string{srv3.URL + "/", srv2.URL + "/"}, c.URLs())
+	assert.Equal(t, srv2.URL+"/", c.GetCurrentURL())
+	assert.Error(t, c.SetURLs(nil))
+
+	assert.NoError(t, c.RemoveURL(srv3.URL))
+	assert.Error(t, c.RemoveURL(srv2.URL))
+	assert.Equal(t, []string{srv2.URL + "/"}, c.URLs())
+
+	close(stop)
+	wg.Wait()
+}
+
+type fakeResolver struct{}
+
+func (fakeResolver) LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	return "", []*net.SRV{{Target: "b.example.com.", Port: 8081}, {Target: "a.example.com.", Port: 8080}}, nil
+}
+
+func (fakeResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	return []string{"10.0.0.2", "::1"}, nil
+}
+
+func TestDiscovery(t *testing.T) {
+	ctx := context.Background()
+
+	urls, err := Static("http://a", "http://b").URLs(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"http://a", "http://b"}, urls)
+
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "urls.json"), []byte(`["http://a", "http://b"]`), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "urls.yaml"), []byte("- http://c\n"), 0o600))
+
+	urls, err = File(filepath.Join(dir, "urls.json")).URLs(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"http://a", "http://b"}, urls)
+	urls, err = File(filepath.Join(dir, "urls.yaml")).URLs(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"http://c"}, urls)
+
+	srv := DNSSRV("api", "tcp", "example.com")
+	srv.Resolver = fakeResolver{}
+	urls, err = srv.URLs(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"http://a.example.com:8080/", "http://b.example.com:8081/"}, urls)
+
+	a := DNSA("example.com", 80)
+	a.Resolver = fakeResolver{}
+	a.Scheme = "https"
+	urls, err = a.URLs(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"https://10.0.0.2:80/", "https://[::1]:80/"}, urls)
+}
+
+func TestClientDiscovery(t *testing.T) {
+	srv1 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv1.Close()
+	srv2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv2.Close()
+
+	file := filepath.Join(t.TempDir(), "urls.json")
+	assert.NoError(t, os.WriteFile(file, []byte(`["`+srv1.URL+`"]`), 0o600))
+
+	c, err := NewClientWithConfig(nil, Config{Discovery: File(file), DiscoveryInterval: time.Hour})
+	assert.NoError(t, err)
+	defer c.Close()
+	assert.Equal(t, srv1.URL+"/", c.GetCurrentURL())
+
+	assert.NoError(t, os.WriteFile(file, []byte(`["`+srv2.URL+`"]`), 0o600))
+	assert.Eventually(t, func() bool { return c.GetCurrentURL() == srv2.URL+"/" }, 2*time.Second, 5*time.Millisecond)
+
+	renamed := file + ".tmp"
+	assert.NoError(t, os.WriteFile(renamed, []byte(`["`+srv1.URL+`"]`), 0o600))
+	assert.NoError(t, os.Rename(renamed, file))
+	assert.Eventually(t, func() bool { return c.GetCurrentURL() == srv1.URL+"/" }, 2*time.Second, 5*time.Millisecond)
+}
@@ -0,0 +1,189 @@
+package balancer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+	"gopkg.in/yaml.v3"
+)
+
+// Source provides the backend URLs of a client, see Config.Discovery.
+type Source interface {
+	URLs(ctx context.Context) ([]string, error)
+}
+
+// Watcher is a Source that also reports changes between the lookups.
+type Watcher interface {
+	Source
+	// Watch returns a channel receiving a value after every change. It is
+	// closed once ctx is done and the watch has stopped.
+	Watch(ctx context.Context) (<-chan struct{}, error)
+}
+
+type staticSource []string
+
+// Static always returns urls.
+func Static(urls ...string) Source {
+	return staticSource(urls)
+}
+
+func (s staticSource) URLs(context.Context) ([]string, error) {
+	return append([]string(nil), s...), nil
+}
+
+type fileSource string
+
+// File reads a JSON or YAML list of URLs from path. The file is watched, so
+// changes are applied right away, and polled every DiscoveryInterval in case
+// the watch fails.
+func File(path string) Source {
+	return fileSource(path)
+}
+
+func (s fileSource) URLs(context.Context) ([]string, error) {
+	data, err := os.ReadFile(string(s))
+	if err != nil {
+		return nil, err
+	}
+
+	var urls []string
+	switch strings.ToLower(filepath.Ext(string(s))) {
+	case ".json":
+		err = json.Unmarshal(data, &urls)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &urls)
+	default:
+		return nil, fmt.Errorf("balancer: unsupported url file %s", s)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return urls, nil
+}
+
+// Watch watches the directory of the file, so that replacing the file, as
+// editors and config management do, is noticed too.
+func (s fileSource) Watch(ctx context.Context) (<-chan struct{}, error) {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := w.Add(filepath.Dir(string(s))); err != nil {
+		_ = w.Close()
+		return nil, err
+	}
+
+	name := filepath.Clean(string(s))
+	changes := make(chan struct{}, 1)
+	go func() {
+		defer close(changes)
+		defer w.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev, ok := <-w.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(ev.Name) != name {
+					continue
+				}
+				select {
+				case changes <- struct{}{}:
+				default:
+				}
+			case _, ok := <-w.Errors:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+
+	return changes, nil
+}
+
+// Resolver is the subset of net.Resolver used by DNSSource.
+type Resolver interface {
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// DNSSource resolves backends from DNS. With Service set it looks up the SRV
+// records of _Service._Proto.Name, otherwise the A and AAAA records of Name
+// combined with Port, if any.
+type DNSSource struct {
+	Name    string
+	Service string
+	Proto   string
+	Port    int
+	// Scheme of the URLs, http by default.
+	Scheme string
+	// Resolver defaults to net.DefaultResolver.
+	Resolver Resolver
+}
+
+// DNSSRV returns a source of the SRV records of _service._proto.name.
+func DNSSRV(service, proto, name string) *DNSSource {
+	return &DNSSource{Name: name, Service: service, Proto: proto}
+}
+
+// DNSA returns a source of the addresses of host with port.
+func DNSA(host string, port int) *DNSSource {
+	return &DNSSource{Name: host, Port: port}
+}
+
+// URLs returns the resolved URLs sorted, so that reordered answers do not change the backends.
+func (s *DNSSource) URLs(ctx context.Context) ([]string, error) {
+	resolver := s.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	scheme := s.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+
+	var hosts []string
+	if s.Service != "" {
+		_, records, err := resolver.LookupSRV(ctx, s.Service, s.Proto, s.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range records {
+			hosts = append(hosts, net.JoinHostPort(strings.TrimSuffix(r.Target, "."), strconv.Itoa(int(r.Port))))
+		}
+	} else {
+		addrs, err := resolver.LookupHost(ctx, s.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, addr := range addrs {
+			switch {
+			case s.Port != 0:
+				hosts = append(hosts, net.JoinHostPort(addr, strconv.Itoa(s.Port)))
+			case strings.Contains(addr, ":"):
+				hosts = append(hosts, "["+addr+"]")
+			default:
+				hosts = append(hosts, addr)
+			}
+		}
+	}
+
+	urls := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		urls = append(urls, scheme+"://"+host+"/")
+	}
+	sort.Strings(urls)
+	return urls, nil
+}
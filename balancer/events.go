@@ -0,0 +1,160 @@
+package balancer
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultEventBuffer is the buffer of a subscription created with a non-positive size.
+const DefaultEventBuffer = 16
+
+type EventType int
+
+const (
+	// EventBackendUp is sent when a backend passes its health checks, URL is the backend.
+	EventBackendUp EventType = iota + 1
+	// EventBackendDown is sent when a backend fails its health checks, URL is the backend.
+	EventBackendDown
+	// EventActiveChanged is sent when the active backend changes from From to To.
+	// From is empty when the client recovers.
+	EventActiveChanged
+	// EventAllDown is sent when the active backend From is lost and no backend is healthy.
+	EventAllDown
+	// EventRecovered is sent when To becomes active after all backends were down.
+	EventRecovered
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventBackendUp:
+		return "backend up"
+	case EventBackendDown:
+		return "backend down"
+	case EventActiveChanged:
+		return "active changed"
+	case EventAllDown:
+		return "all down"
+	case EventRecovered:
+		return "recovered"
+	default:
+		return "unknown"
+	}
+}
+
+type Event struct {
+	Type EventType
+	URL  string
+	From string
+	To   string
+	Time time.Time
+}
+
+// DropPolicy decides which event is lost when a subscriber falls behind.
+type DropPolicy int
+
+const (
+	// DropNewest discards the event that does not fit into the buffer.
+	DropNewest DropPolicy = iota
+	// DropOldest discards the oldest buffered event to make room for the new one.
+	DropOldest
+)
+
+// Subscription delivers events to Events without ever blocking the client.
+type Subscription struct {
+	mu      sync.Mutex
+	ch      chan Event
+	policy  DropPolicy
+	closed  bool
+	dropped atomic.Uint64
+}
+
+// Events is closed by Unsubscribe and Close.
+func (s *Subscription) Events() <-chan Event {
+	return s.ch
+}
+
+// Dropped returns the number of events lost because the buffer was full.
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+func (s *Subscription) deliver(ev Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+
+	select {
+	case s.ch <- ev:
+		return
+	default:
+	}
+
+	if s.policy == DropOldest {
+		select {
+		case <-s.ch:
+		default:
+		}
+		select {
+		case s.ch <- ev:
+		default:
+		}
+	}
+	s.dropped.Add(1)
+}
+
+func (s *Subscription) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.ch)
+	}
+}
+
+// Subscribe returns a subscription buffering up to buffer events.
+// On a closed client the subscription is already closed.
+func (c *Client) Subscribe(buffer int, policy DropPolicy) *Subscription {
+	if buffer <= 0 {
+		buffer = DefaultEventBuffer
+	}
+	s := &Subscription{ch: make(chan Event, buffer), policy: policy}
+
+	c.lifeMu.Lock()
+	defer c.lifeMu.Unlock()
+	if c.closed {
+		s.close()
+		return s
+	}
+
+	c.mu.Lock()
+	c.subscriptions[s] = struct{}{}
+	c.mu.Unlock()
+	return s
+}
+
+// Unsubscribe stops delivery to s and closes its channel.
+func (c *Client) Unsubscribe(s *Subscription) {
+	c.mu.Lock()
+	delete(c.subscriptions, s)
+	c.mu.Unlock()
+
+	s.close()
+}
+
+func (c *Client) publish(ev Event) {
+	ev.Time = time.Now()
+
+	c.mu.RLock()
+	subscriptions := make([]*Subscription, 0, len(c.subscriptions))
+	for s := range c.subscriptions {
+		subscriptions = append(subscriptions, s)
+	}
+	c.mu.RUnlock()
+
+	for _, s := range subscriptions {
+		s.deliver(ev)
+	}
+}
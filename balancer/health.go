@@ -0,0 +1,133 @@
+package balancer
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxHealthBody is the most of a health check response read by MatchBody.
+const maxHealthBody = 1 << 20
+
+// HealthCheck configures the probe of every backend. Zero fields take the defaults.
+type HealthCheck struct {
+	// Path is appended to the backend URL, empty probes the URL itself.
+	Path string
+	// Method defaults to GET.
+	Method string
+	// StatusMin and StatusMax bound the healthy status codes, 200-399 by default.
+	StatusMin int
+	StatusMax int
+	// MatchBody, if set, must accept the response body for the backend to be healthy.
+	MatchBody func(body []byte) bool
+	// Interval between probes of a backend, 10s by default.
+	Interval time.Duration
+	// Timeout of a probe, 3s by default.
+	Timeout time.Duration
+	// Rise is the number of passed probes in a row before a down backend is up, 1 by default.
+	Rise int
+	// Fall is the number of failed probes in a row before an up backend is down, 1 by default.
+	Fall int
+}
+
+func (h HealthCheck) withDefaults() HealthCheck {
+	if h.Method == "" {
+		h.Method = http.MethodGet
+	}
+	if h.StatusMin == 0 && h.StatusMax == 0 {
+		h.StatusMin, h.StatusMax = http.StatusOK, 399
+	}
+	if h.StatusMax == 0 {
+		h.StatusMax = h.StatusMin
+	}
+	if h.Interval <= 0 {
+		h.Interval = 10 * time.Second
+	}
+	if h.Timeout <= 0 {
+		h.Timeout = 3 * time.Second
+	}
+	if h.Rise <= 0 {
+		h.Rise = 1
+	}
+	if h.Fall <= 0 {
+		h.Fall = 1
+	}
+	return h
+}
+
+// probe reports whether the backend at baseURL passes the check.
+func (h HealthCheck) probe(ctx context.Context, client *http.Client, baseURL string) bool {
+	reqCtx, cancel := context.WithTimeout(ctx, h.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(reqCtx, h.Method, baseURL+strings.TrimLeft(h.Path, "/"), nil)
+	if err != nil {
+		return false
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < h.StatusMin || resp.StatusCode > h.StatusMax {
+		return false
+	}
+
+	if h.MatchBody != nil {
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxHealthBody))
+		if err != nil {
+			return false
+		}
+		return h.MatchBody(body)
+	}
+
+	return true
+}
+
+// report records a probe result and reports whether the backend changed state.
+// The first probe sets the state, later ones need Rise or Fall results in a row.
+func (b *Backend) report(passed bool, rise, fall int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	healthy := b.healthy.Load()
+	if !b.checked {
+		b.checked = true
+		b.healthy.Store(passed)
+		return passed != healthy
+	}
+
+	if passed {
+		b.failures = 0
+		b.successes++
+		if !healthy && b.successes >= rise {
+			b.healthy.Store(true)
+			return true
+		}
+		return false
+	}
+
+	b.successes = 0
+	b.failures++
+	if healthy && b.failures >= fall {
+		b.healthy.Store(false)
+		return true
+	}
+	return false
+}
+
+// markDown takes the backend down until Rise probes pass, it reports whether
+// the backend was up.
+func (b *Backend) markDown() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.checked = true
+	b.successes = 0
+	b.failures = 0
+	return b.healthy.Swap(false)
+}
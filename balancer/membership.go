@@ -0,0 +1,203 @@
+package balancer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+func normalizeURL(u string) (string, error) {
+	if u == "" {
+		return "", errors.New("empty balancer url")
+	}
+	if !strings.HasSuffix(u, "/") {
+		u += "/"
+	}
+	return u, nil
+}
+
+// newBackend returns a backend whose checks stop when it is removed or the client is closed.
+func (c *Client) newBackend(u string) *Backend {
+	b := newBackend(u)
+	b.ctx, b.cancel = context.WithCancel(c.ctx)
+	return b
+}
+
+// AddURL adds a backend, it takes requests once its first health check passes.
+func (c *Client) AddURL(u string) error {
+	u, err := normalizeURL(u)
+	if err != nil {
+		return err
+	}
+
+	return c.changeURLs(func(urls []string) ([]string, error) {
+		if slices.Contains(urls, u) {
+			return urls, nil
+		}
+		return append(urls, u), nil
+	})
+}
+
+// RemoveURL removes a backend. Removing the active backend fails over to the
+// next healthy one, the last backend cannot be removed.
+func (c *Client) RemoveURL(u string) error {
+	u, err := normalizeURL(u)
+	if err != nil {
+		return err
+	}
+
+	return c.changeURLs(func(urls []string) ([]string, error) {
+		i := slices.Index(urls, u)
+		if i == -1 {
+			return nil, fmt.Errorf("balancer url %s not found", u)
+		}
+		if len(urls) == 1 {
+			return nil, errors.New("cannot remove the last balancer url")
+		}
+		return slices.Delete(urls, i, i+1), nil
+	})
+}
+
+// SetURLs replaces the backends, the ones kept keep their health and statistics.
+func (c *Client) SetURLs(urls []string) error {
+	if len(urls) == 0 {
+		return errors.New("no urls provided")
+	}
+
+	next := make([]string, 0, len(urls))
+	for _, u := range urls {
+		u, err := normalizeURL(u)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(next, u) {
+			next = append(next, u)
+		}
+	}
+
+	return c.changeURLs(func([]string) ([]string, error) {
+		return next, nil
+	})
+}
+
+// URLs returns the URLs of the backends.
+func (c *Client) URLs() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.urls()
+}
+
+func (c *Client) changeURLs(change func(urls []string) ([]string, error)) error {
+	if c.isClosed() {
+		return ErrClientClosed
+	}
+
+	c.mu.Lock()
+	urls, err := change(c.urls())
+	if err != nil {
+		c.mu.Unlock()
+		return err
+	}
+
+	existing := make(map[string]*Backend, len(c.backends))
+	for _, b := range c.backends {
+		existing[b.url] = b
+	}
+
+	var added []*Backend
+	backends := make([]*Backend, 0, len(urls))
+	for _, u := range urls {
+		b, ok := existing[u]
+		if ok {
+			delete(existing, u)
+		} else {
+			b = c.newBackend(u)
+			added = append(added, b)
+		}
+		backends = append(backends, b)
+	}
+
+	var active *Backend
+	idx := c.currentIdx
+	if idx >= 0 && idx < len(c.backends) {
+		active = c.backends[idx]
+	}
+
+	c.backends = backends
+	c.currentIdx = slices.Index(backends, active)
+
+	from, to := "", ""
+	activeRemoved := active != nil && c.currentIdx == -1
+	if activeRemoved {
+		from = active.url
+		if c.switchToHealthy(min(idx, len(backends)) - 1) {
+			to = c.backends[c.currentIdx].url
+		}
+	}
+	c.mu.Unlock()
+
+	for _, b := range existing {
+		b.cancel()
+	}
+	for _, b := range added {
+		c.spawn(func() {
+			c.checkBackend(b.ctx, b)
+			c.monitorBackend(b)
+		})
+	}
+
+	switch {
+	case activeRemoved && to != "":
+		c.publish(Event{Type: EventActiveChanged, From: from, To: to})
+	case activeRemoved:
+		c.publish(Event{Type: EventAllDown, From: from})
+	}
+
+	return nil
+}
+
+// urls returns the URLs of the backends, c.mu must be held.
+func (c *Client) urls() []string {
+	urls := make([]string, 0, len(c.backends))
+	for _, b := range c.backends {
+		urls = append(urls, b.url)
+	}
+	return urls
+}
+
+// discover applies the URLs of src every interval and after every value of
+// changes, if any. Failed lookups and empty lists keep the current backends.
+func (c *Client) discover(src Source, interval time.Duration, changes <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			if changes != nil {
+				for range changes {
+				}
+			}
+			return
+		case <-ticker.C:
+			c.rediscover(src)
+		case _, ok := <-changes:
+			if !ok {
+				changes = nil
+				continue
+			}
+			c.rediscover(src)
+		}
+	}
+}
+
+func (c *Client) rediscover(src Source) {
+	urls, err := src.URLs(c.ctx)
+	if err != nil || len(urls) == 0 {
+		return
+	}
+	_ = c.SetURLs(urls)
+}
@@ -0,0 +1,229 @@
+package balancer
+
+import (
+	"context"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ewmaWeight is the weight of the newest latency sample.
+const ewmaWeight = 0.3
+
+// Backend is a server of the Client as seen by a Strategy.
+type Backend struct {
+	url         string
+	ctx         context.Context
+	cancel      context.CancelFunc
+	healthy     atomic.Bool
+	outstanding atomic.Int64
+	latency     atomic.Int64
+
+	mu        sync.Mutex
+	checked   bool
+	successes int
+	failures  int
+}
+
+func newBackend(url string) *Backend {
+	return &Backend{url: url, ctx: context.Background(), cancel: func() {}}
+}
+
+func (b *Backend) URL() string {
+	return b.url
+}
+
+func (b *Backend) Healthy() bool {
+	return b.healthy.Load()
+}
+
+// Outstanding returns the number of requests sent to the backend whose
+// response body is not closed yet.
+func (b *Backend) Outstanding() int64 {
+	return b.outstanding.Load()
+}
+
+// Latency returns the exponentially weighted moving average of the time to
+// the response headers, 0 until the first response. A failed request counts
+// as a response as slow as the request timeout.
+func (b *Backend) Latency() time.Duration {
+	return time.Duration(b.latency.Load())
+}
+
+func (b *Backend) observe(d time.Duration) {
+	for {
+		old := b.latency.Load()
+		next := int64(d)
+		if old != 0 {
+			next = int64(ewmaWeight*float64(d) + (1-ewmaWeight)*float64(old))
+		}
+		if b.latency.CompareAndSwap(old, next) {
+			return
+		}
+	}
+}
+
+// fail records a failed request as a sample of at least penalty.
+func (b *Backend) fail(penalty time.Duration) {
+	b.observe(max(penalty, b.Latency()))
+}
+
+// Strategy chooses the backend of every request.
+type Strategy interface {
+	// Next returns one of backends. They are healthy, never empty and listed
+	// in URL order starting from the active backend.
+	Next(backends []*Backend) *Backend
+}
+
+type failover struct{}
+
+// Failover sends every request to the active backend until it goes down.
+func Failover() Strategy {
+	return failover{}
+}
+
+func (failover) Next(backends []*Backend) *Backend {
+	return backends[0]
+}
+
+type roundRobin struct {
+	next atomic.Uint64
+}
+
+// RoundRobin sends requests to every healthy backend in turn.
+func RoundRobin() Strategy {
+	return &roundRobin{}
+}
+
+func (s *roundRobin) Next(backends []*Backend) *Backend {
+	return backends[(s.next.Add(1)-1)%uint64(len(backends))]
+}
+
+type weightedRoundRobin struct {
+	mu      sync.Mutex
+	weights map[string]int
+	current map[string]int
+}
+
+// WeightedRoundRobin sends requests in proportion to weights keyed by URL,
+// spreading them evenly (smooth weighted round-robin). Backends without a
+// positive weight have weight 1.
+func WeightedRoundRobin(weights map[string]int) Strategy {
+	s := &weightedRoundRobin{weights: map[string]int{}, current: map[string]int{}}
+	for u, w := range weights {
+		if !strings.HasSuffix(u, "/") {
+			u += "/"
+		}
+		s.weights[u] = w
+	}
+	return s
+}
+
+func (s *weightedRoundRobin) weight(b *Backend) int {
+	if w := s.weights[b.url]; w > 0 {
+		return w
+	}
+	return 1
+}
+
+func (s *weightedRoundRobin) Next(backends []*Backend) *Backend {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var best *Backend
+	total := 0
+	for _, b := range backends {
+		w := s.weight(b)
+		total += w
+		s.current[b.url] += w
+		if best == nil || s.current[b.url] > s.current[best.url] {
+			best = b
+		}
+	}
+	s.current[best.url] -= total
+
+	if len(s.current) > len(backends) {
+		s.prune(backends)
+	}
+
+	return best
+}
+
+// prune forgets the backends that are removed or down, a backend coming back starts afresh.
+func (s *weightedRoundRobin) prune(backends []*Backend) {
+	keep := make(map[string]struct{}, len(backends))
+	for _, b := range backends {
+		keep[b.url] = struct{}{}
+	}
+	for u := range s.current {
+		if _, ok := keep[u]; !ok {
+			delete(s.current, u)
+		}
+	}
+}
+
+type leastOutstanding struct{}
+
+// LeastOutstanding sends a request to the backend with the fewest requests in flight.
+func LeastOutstanding() Strategy {
+	return leastOutstanding{}
+}
+
+func (leastOutstanding) Next(backends []*Backend) *Backend {
+	best := backends[0]
+	for _, b := range backends[1:] {
+		if b.Outstanding() < best.Outstanding() {
+			best = b
+		}
+	}
+	return best
+}
+
+type ewma struct{}
+
+// EWMA sends a request to the backend with the lowest average latency weighted
+// by its requests in flight. Backends without responses yet are tried first.
+func EWMA() Strategy {
+	return ewma{}
+}
+
+func (ewma) Next(backends []*Backend) *Backend {
+	best := backends[0]
+	for _, b := range backends[1:] {
+		if ewmaCost(b) < ewmaCost(best) {
+			best = b
+		}
+	}
+	return best
+}
+
+func ewmaCost(b *Backend) float64 {
+	return float64(b.Latency()) * float64(b.Outstanding()+1)
+}
+
+type randomTwoChoices struct{}
+
+// RandomTwoChoices picks two random backends and sends the request to the one
+// with fewer requests in flight.
+func RandomTwoChoices() Strategy {
+	return randomTwoChoices{}
+}
+
+func (randomTwoChoices) Next(backends []*Backend) *Backend {
+	if len(backends) == 1 {
+		return backends[0]
+	}
+
+	i := rand.IntN(len(backends))
+	j := rand.IntN(len(backends) - 1)
+	if j >= i {
+		j++
+	}
+
+	if backends[j].Outstanding() < backends[i].Outstanding() {
+		return backends[j]
+	}
+	return backends[i]
+}
@@ -0,0 +1,168 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"strings"
+	"syscall"
+
+	"github.com/c2pc/go-pkg/apperr"
+	"github.com/c2pc/go-pkg/apperr/utils/appErrors"
+	"github.com/c2pc/go-pkg/apperr/utils/code"
+	"github.com/c2pc/go-pkg/apperr/utils/translate"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAlreadyExists = apperr.Register(apperr.New("already_exists_error",
+		apperr.WithTextTranslate(translate.Translate{translate.RU: "Запись уже существует"}),
+		apperr.WithCode(code.AlreadyExists),
+	))
+	ErrForeignKeyViolation = apperr.Register(apperr.New("foreign_key_violation_error",
+		apperr.WithTextTranslate(translate.Translate{translate.RU: "Нарушена связь с другой записью"}),
+		apperr.WithCode(code.FailedPrecondition),
+	))
+	ErrCheckViolation = apperr.Register(apperr.New("check_violation_error",
+		apperr.WithTextTranslate(translate.Translate{translate.RU: "Нарушено ограничение данных"}),
+		apperr.WithCode(code.FailedPrecondition),
+	))
+	ErrTransactionAborted = apperr.Register(apperr.New("transaction_aborted_error",
+		apperr.WithTextTranslate(translate.Translate{translate.RU: "Операция прервана, повторите запрос"}),
+		apperr.WithCode(code.Aborted),
+	))
+	ErrDatabaseIsNotAvailable = apperr.Register(apperr.New("database_is_not_available",
+		apperr.WithTextTranslate(translate.Translate{translate.RU: "База данных недоступна"}),
+		apperr.WithCode(code.Unavailable),
+	))
+)
+
+// Postgres SQLSTATE codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgUniqueViolation      = "23505"
+	pgForeignKeyViolation  = "23503"
+	pgCheckViolation       = "23514"
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+	pgTooManyConnections   = "53300"
+	pgAdminShutdown        = "57P01"
+	pgCrashShutdown        = "57P02"
+	pgCannotConnectNow     = "57P03"
+	pgConnectionException  = "08"
+)
+
+type pgError struct {
+	code       string
+	constraint string
+	table      string
+	column     string
+}
+
+// ParseError translates gorm, lib/pq and pgx errors into an apperr.Error wrapping err.
+// Errors it does not recognize become appErrors.ErrInternal, nil stays nil.
+func ParseError(err error) *apperr.Error {
+	if err == nil {
+		return nil
+	}
+
+	var appError apperr.Error
+	if errors.As(err, &appError) {
+		return &appError
+	}
+
+	parsed := parseError(err)
+	return &parsed
+}
+
+func parseError(err error) apperr.Error {
+
+	if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, sql.ErrNoRows) {
+		return appErrors.ErrNotFound.WithError(err)
+	}
+
+	if pgErr, ok := asPgError(err); ok {
+		switch {
+		case pgErr.code == pgUniqueViolation:
+			return withConstraint(ErrAlreadyExists, pgErr).WithError(err)
+		case pgErr.code == pgForeignKeyViolation:
+			return withConstraint(ErrForeignKeyViolation, pgErr).WithError(err)
+		case pgErr.code == pgCheckViolation:
+			return withConstraint(ErrCheckViolation, pgErr).WithError(err)
+		case pgErr.code == pgSerializationFailure, pgErr.code == pgDeadlockDetected:
+			return ErrTransactionAborted.WithError(err)
+		case strings.HasPrefix(pgErr.code, pgConnectionException),
+			pgErr.code == pgTooManyConnections,
+			pgErr.code == pgAdminShutdown,
+			pgErr.code == pgCrashShutdown,
+			pgErr.code == pgCannotConnectNow:
+			return ErrDatabaseIsNotAvailable.WithError(err)
+		}
+	}
+
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ErrAlreadyExists.WithError(err)
+	}
+	if errors.Is(err, gorm.ErrForeignKeyViolated) {
+		return ErrForeignKeyViolation.WithError(err)
+	}
+
+	if isConnectionError(err) {
+		return ErrDatabaseIsNotAvailable.WithError(err)
+	}
+
+	return appErrors.ErrInternal.WithError(err)
+}
+
+func asPgError(err error) (pgError, bool) {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pgError{
+			code:       string(pqErr.Code),
+			constraint: pqErr.Constraint,
+			table:      pqErr.Table,
+			column:     pqErr.Column,
+		}, true
+	}
+
+	var pgxErr *pgconn.PgError
+	if errors.As(err, &pgxErr) {
+		return pgError{
+			code:       pgxErr.Code,
+			constraint: pgxErr.ConstraintName,
+			table:      pgxErr.TableName,
+			column:     pgxErr.ColumnName,
+		}, true
+	}
+
+	return pgError{}, false
+}
+
+func withConstraint(err apperr.Error, pgErr pgError) apperr.Error {
+	err = apperr.Replace(err, apperr.WithContext(pgErr.constraint))
+	if pgErr.constraint != "" {
+		err = err.WithField("constraint", pgErr.constraint)
+	}
+	if pgErr.table != "" {
+		err = err.WithField("table", pgErr.table)
+	}
+	if pgErr.column != "" {
+		err = err.WithField("column", pgErr.column)
+	}
+	return err
+}
+
+func isConnectionError(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, sql.ErrConnDone) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+
+	// pgx wraps dial failures into a connectError unwrapping to the net.Error.
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/c2pc/go-pkg/apperr"
+	"github.com/c2pc/go-pkg/apperr/utils/appErrors"
+	"github.com/c2pc/go-pkg/apperr/utils/code"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestParseError(t *testing.T) {
+	assert.Nil(t, ParseError(nil))
+
+	tests := []struct {
+		err  error
+		id   string
+		code code.Code
+	}{
+		{gorm.ErrRecordNotFound, appErrors.ErrNotFound.ID, code.NotFound},
+		{&pq.Error{Code: "23505", Constraint: "users_login_key"}, ErrAlreadyExists.ID, code.AlreadyExists},
+		{fmt.Errorf("create: %w", &pgconn.PgError{Code: "23505", ConstraintName: "users_login_key"}), ErrAlreadyExists.ID, code.AlreadyExists},
+		{&pgconn.PgError{Code: "23503"}, ErrForeignKeyViolation.ID, code.FailedPrecondition},
+		{&pq.Error{Code: "23514"}, ErrCheckViolation.ID, code.FailedPrecondition},
+		{&pq.Error{Code: "40001"}, ErrTransactionAborted.ID, code.Aborted},
+		{&pgconn.PgError{Code: "40P01"}, ErrTransactionAborted.ID, code.Aborted},
+		{&pgconn.PgError{Code: "08006"}, ErrDatabaseIsNotAvailable.ID, code.Unavailable},
+		{&net.OpError{Op: "dial", Err: errors.New("connection refused")}, ErrDatabaseIsNotAvailable.ID, code.Unavailable},
+		{errors.New("boom"), appErrors.ErrInternal.ID, code.Internal},
+	}
+
+	for _, tt := range tests {
+		appErr := ParseError(tt.err)
+		assert.Equal(t, tt.id, appErr.ID, tt.err.Error())
+		assert.Equal(t, tt.code, appErr.Code, tt.err.Error())
+		assert.Equal(t, tt.err, appErr.LastError())
+	}
+
+	appErr := ParseError(&pq.Error{Code: "23505", Constraint: "users_login_key"})
+	assert.Equal(t, "users_login_key", appErr.Context)
+	assert.Equal(t, apperr.Fields{"constraint": "users_login_key"}, appErr.Fields)
+
+	connErr := &net.OpError{Op: "dial", Err: errors.New("connection refused")}
+	assert.Equal(t, ErrDatabaseIsNotAvailable.ID, ParseError(fmt.Errorf("failed to connect to `host=db`: %w", connErr)).ID)
+	assert.Equal(t, appErrors.ErrInternal.ID, ParseError(errors.New("failed to connect to nothing")).ID)
+	assert.Equal(t, ErrAlreadyExists.ID, ParseError(ErrAlreadyExists).ID)
+}
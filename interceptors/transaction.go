@@ -5,9 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 
-	"github.com/c2pc/go-pkg/apperr"
 	"github.com/c2pc/go-pkg/apperr/utils/appErrors"
-	"github.com/c2pc/go-pkg/apperr/utils/translate"
 	"github.com/c2pc/go-pkg/apperr/x/grpcerr"
 	"github.com/gin-gonic/gin"
 	"google.golang.org/grpc"
@@ -15,10 +13,7 @@ import (
 )
 
 var (
-	ErrInternalMethod = apperr.New("all",
-		apperr.WithTitleTranslate(translate.Translate{translate.RU: "Ошибка"}),
-		apperr.WithContext("all"),
-	)
+	ErrInternalMethod = appErrors.ErrInternalMethod
 
 	ErrCommitDatabaseID = "commit_database_error"
 	ErrPanicID          = "panic_error"
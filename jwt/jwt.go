@@ -14,12 +14,12 @@ const AuthUserKey = "authUser"
 const authorizationHeader = "Authorization"
 
 var (
-	ErrEmptyAuthHeader      = apperr.New("empty_auth_header")
-	ErrInvalidAuthHeader    = apperr.New("invalid_auth_header")
-	ErrEmptyToken           = apperr.New("empty_token")
-	ErrInvalidToken         = apperr.New("invalid_token")
-	ErrTokenParseError      = apperr.New("token_parse_error")
-	ErrErrorToSigningString = apperr.New("error_to_signing_string")
+	ErrEmptyAuthHeader      = apperr.Register(apperr.New("empty_auth_header"))
+	ErrInvalidAuthHeader    = apperr.Register(apperr.New("invalid_auth_header"))
+	ErrEmptyToken           = apperr.Register(apperr.New("empty_token"))
+	ErrInvalidToken         = apperr.Register(apperr.New("invalid_token"))
+	ErrTokenParseError      = apperr.Register(apperr.New("token_parse_error"))
+	ErrErrorToSigningString = apperr.Register(apperr.New("error_to_signing_string"))
 )
 
 type JWT struct {
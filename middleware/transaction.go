@@ -5,19 +5,14 @@ import (
 	"fmt"
 	"net/http"
 
-	"github.com/c2pc/go-pkg/apperr"
 	"github.com/c2pc/go-pkg/apperr/utils/appErrors"
-	"github.com/c2pc/go-pkg/apperr/utils/translate"
 	"github.com/c2pc/go-pkg/apperr/x/httperr"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
 var (
-	ErrInternalMethod = apperr.New("all",
-		apperr.WithTitleTranslate(translate.Translate{translate.RU: "Ошибка"}),
-		apperr.WithContext("all"),
-	)
+	ErrInternalMethod = appErrors.ErrInternalMethod
 
 	ErrCommitDatabaseID = "commit_database_error"
 	ErrPanicID          = "panic_error"
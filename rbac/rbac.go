@@ -12,17 +12,17 @@ import (
 )
 
 var (
-	ErrUnauthorizedMethod = apperr.New("auth",
+	ErrUnauthorizedMethod = apperr.Register(apperr.New("auth",
 		apperr.WithTitleTranslate(translate.Translate{translate.RU: "Попытка авторизации"}),
 		apperr.WithContext("auth"),
-	)
+	))
 
-	ErrForbiddenMethod = apperr.New("auth",
+	ErrForbiddenMethod = apperr.Register(apperr.New("forbidden_method",
 		apperr.WithTitleTranslate(translate.Translate{translate.RU: "Попытка авторизации"}),
 		apperr.WithContext("auth"),
-	)
+	))
 
-	ErrErrorToGetUserFromContext = apperr.New("error_to_get_user_from_context")
+	ErrErrorToGetUserFromContext = apperr.Register(apperr.New("error_to_get_user_from_context"))
 )
 
 type AuthUser struct {